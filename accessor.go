@@ -0,0 +1,177 @@
+package sqlevaluator
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// FieldGetter 模型可以实现该接口按列名返回字段值，评估器优先使用它代替反射读取字段
+//
+// name 为SQL中的列名，限定列名以点连接（如 address.city）。列不存在时返回false，评估返回字段未找到错误；
+// 返回的值按与结构体字段相同的规则转换，nil表示NULL。
+type FieldGetter interface {
+	GetField(name string) (interface{}, bool)
+}
+
+// fieldGetterInterface FieldGetter 接口的类型
+var fieldGetterInterface = reflect.TypeOf((*FieldGetter)(nil)).Elem()
+
+// columnKey 列解析缓存的键
+type columnKey struct {
+	modelType reflect.Type
+	column    string
+}
+
+// columnInfo 结构体模型中列的解析结果
+type columnInfo struct {
+	// indexes 每一级字段的索引
+	indexes [][]int
+	// field 最后一级的字段
+	field reflect.StructField
+	// err 列不存在时的错误
+	err error
+}
+
+// columnCache 缓存结构体类型中列的解析结果，同一类型的同一列只需解析一次
+//
+// 评估时按模型类型缓存完整的列，findStructField 按结构体类型缓存单级列名。只缓存能解析到字段的列，
+// 不存在的列和路径上有map或接口的列每次重新解析，因此任意的列名不会使缓存增长。
+var columnCache sync.Map
+
+// getColumnValue 获取列的值
+//
+// 模型实现了 FieldGetter 时调用 GetField；路径上只有结构体和结构体指针的列按类型缓存字段索引，
+// 评估时直接按索引读取；路径上有map或接口时按 getFieldName 和 getFieldValue 逐级解析。
+func (e *SQLEvaluator) getColumnValue(col *sqlparser.ColName) (interface{}, error) {
+	if getter, ok := e.model.(FieldGetter); ok {
+		name := columnPath(col)
+		value, _, ok := getFieldQualified(getter, name)
+		if !ok {
+			return nil, newFieldNotFound(name)
+		}
+		if value == nil {
+			return nil, nil
+		}
+		return normalizeValue(reflect.ValueOf(value))
+	}
+
+	if info := resolveColumn(reflect.TypeOf(e.model), columnPath(col)); info != nil {
+		if info.err != nil {
+			return nil, info.err
+		}
+		return readColumn(reflect.ValueOf(e.model), info.indexes)
+	}
+
+	fieldName, err := e.getFieldName(col)
+	if err != nil {
+		return nil, err
+	}
+	return e.getFieldValue(fieldName)
+}
+
+// getFieldQualified 调用 GetField 读取列，列不存在且带有限定名时按去掉限定名的列名重试，如 users.age；
+// 同时返回 GetField 接受的列名
+func getFieldQualified(getter FieldGetter, name string) (interface{}, string, bool) {
+	value, ok := getter.GetField(name)
+	if ok {
+		return value, name, true
+	}
+	if rest, qualified := unqualified(name); qualified {
+		return getFieldQualified(getter, rest)
+	}
+	return nil, "", false
+}
+
+// resolveColumn 返回结构体模型类型中列的解析结果，路径上有map或接口时返回nil
+func resolveColumn(modelType reflect.Type, column string) *columnInfo {
+	if modelType == nil {
+		return nil
+	}
+
+	key := columnKey{modelType: modelType, column: column}
+	if cached, ok := columnCache.Load(key); ok {
+		return cached.(*columnInfo)
+	}
+
+	info := lookupColumn(modelType, column)
+	if info != nil && info.err == nil {
+		columnCache.Store(key, info)
+	}
+	return info
+}
+
+// lookupColumn 按与 getFieldName 相同的规则逐级解析列，路径上有map或接口时返回nil
+func lookupColumn(modelType reflect.Type, column string) *columnInfo {
+	segments := strings.Split(column, ".")
+	info := &columnInfo{indexes: make([][]int, 0, len(segments))}
+
+	current := modelType
+	for i, segment := range segments {
+		for current.Kind() == reflect.Ptr {
+			current = current.Elem()
+		}
+
+		switch current.Kind() {
+		case reflect.Struct:
+			field, ok := lookupStructField(current, segment)
+			if !ok {
+				// 第一级不是字段时按去掉限定名的列名解析，如 users.age
+				if rest, qualified := unqualified(column); i == 0 && qualified {
+					if fallback := lookupColumn(modelType, rest); fallback == nil || fallback.err == nil {
+						return fallback
+					}
+				}
+				info.err = newFieldNotFound(column)
+				return info
+			}
+			info.indexes = append(info.indexes, field.Index)
+			info.field = field
+			current = field.Type
+		case reflect.Map, reflect.Interface:
+			// 需要根据值解析
+			return nil
+		default:
+			info.err = newNotContainer(segments, i)
+			return info
+		}
+	}
+	return info
+}
+
+// readColumn 按字段索引读取列的值，路径上任意一个nil指针（包括匿名嵌入指针）都视为NULL
+func readColumn(current reflect.Value, indexes [][]int) (interface{}, error) {
+	for _, index := range indexes {
+		for current.Kind() == reflect.Ptr {
+			if current.IsNil() {
+				return nil, nil
+			}
+			current = current.Elem()
+		}
+
+		field, err := current.FieldByIndexErr(index)
+		if err != nil {
+			return nil, nil
+		}
+		current = field
+	}
+	return normalizeValue(current)
+}
+
+// ReflectField 通过反射按与评估器相同的规则读取模型中的列，列不存在或无法读取时返回false
+//
+// 供 sqlevalgen 生成的代码读取map、接口等无法静态解析的字段，路径上的nil指针视为NULL。
+func ReflectField(model interface{}, name string) (interface{}, bool) {
+	e := &SQLEvaluator{model: model}
+	fieldName, err := e.resolveFieldPath(name)
+	if err != nil {
+		return nil, false
+	}
+	value, err := e.getFieldValue(fieldName)
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
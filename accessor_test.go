@@ -0,0 +1,197 @@
+package sqlevaluator
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Profile 通过 FieldGetter 暴露字段的模型，字段未导出，无法通过反射读取
+type Profile struct {
+	id      int
+	name    string
+	age     *int
+	city    string
+	balance float32
+}
+
+// GetField 实现 FieldGetter 接口
+func (p *Profile) GetField(name string) (interface{}, bool) {
+	switch name {
+	case "id":
+		return p.id, true
+	case "name":
+		return p.name, true
+	case "age":
+		// nil指针视为NULL
+		return p.age, true
+	case "address.city":
+		return p.city, true
+	case "balance":
+		return p.balance, true
+	case "deleted_at":
+		return nil, true
+	default:
+		return nil, false
+	}
+}
+
+func TestSQLEvaluatorFieldGetter(t *testing.T) {
+	profile := &Profile{
+		id:      7,
+		name:    "张三",
+		city:    "Beijing",
+		balance: 0.1,
+	}
+
+	tests := []struct {
+		name        string
+		whereClause string
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "读取字段",
+			whereClause: "id = 7 AND name = '张三'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "限定列名按完整路径读取",
+			whereClause: "address.city = 'Beijing'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "nil指针视为NULL",
+			whereClause: "age IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "nil视为NULL",
+			whereClause: "deleted_at IS NULL AND id IS NOT NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "返回值按字段的规则转换",
+			whereClause: "balance = 0.1 AND id + 1 = 8",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "字段不存在",
+			whereClause: "agee > 20",
+			want:        false,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(profile)
+			got, err := evaluator.EvaluateWhere(tt.whereClause)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveColumn(t *testing.T) {
+	customerType := reflect.TypeOf(&Customer{})
+
+	tests := []struct {
+		name    string
+		column  string
+		want    [][]int
+		dynamic bool
+		wantErr bool
+	}{
+		{
+			name:    "顶层字段",
+			column:  "name",
+			want:    [][]int{{2}},
+			dynamic: false,
+			wantErr: false,
+		},
+		{
+			name:    "嵌套结构体指针",
+			column:  "billing_address.geo.zip",
+			want:    [][]int{{4}, {1}, {0}},
+			dynamic: false,
+			wantErr: false,
+		},
+		{
+			name:    "匿名嵌入指针提升的字段",
+			column:  "created_by",
+			want:    [][]int{{0, 0}},
+			dynamic: false,
+			wantErr: false,
+		},
+		{
+			name:    "map字段需要按值解析",
+			column:  "metadata.level",
+			dynamic: true,
+			wantErr: false,
+		},
+		{
+			name:    "字段不存在",
+			column:  "address.street",
+			dynamic: false,
+			wantErr: true,
+		},
+		{
+			name:    "访问非结构体字段的子字段",
+			column:  "name.first",
+			dynamic: false,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := resolveColumn(customerType, tt.column)
+			if (info == nil) != tt.dynamic {
+				t.Fatalf("resolveColumn() = %v, dynamic %v", info, tt.dynamic)
+			}
+			if info == nil {
+				if _, cached := columnCache.Load(columnKey{modelType: customerType, column: tt.column}); cached {
+					t.Errorf("resolveColumn() 缓存了需要按值解析的列")
+				}
+				return
+			}
+			if (info.err != nil) != tt.wantErr {
+				t.Errorf("resolveColumn() error = %v, wantErr %v", info.err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(info.indexes, tt.want) {
+				t.Errorf("resolveColumn() = %v, want %v", info.indexes, tt.want)
+			}
+			// 同一类型的同一列只解析一次，不存在的列不缓存
+			_, cached := columnCache.Load(columnKey{modelType: customerType, column: tt.column})
+			if cached == tt.wantErr {
+				t.Errorf("resolveColumn() cached = %v, wantErr %v", cached, tt.wantErr)
+			}
+		})
+	}
+}
+
+// BenchmarkFieldGetter 通过 FieldGetter 读取字段的性能测试
+func BenchmarkFieldGetter(b *testing.B) {
+	compiled := MustCompile("(id > 5 AND name = '张三') OR (address.city = 'Beijing' AND balance > 0)")
+	profile := &Profile{id: 7, name: "张三", city: "Beijing", balance: 0.1}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := compiled.Evaluate(profile); err != nil {
+			b.Fatalf("查询执行失败: %v", err)
+		}
+	}
+}
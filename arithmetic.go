@@ -0,0 +1,280 @@
+package sqlevaluator
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// evaluateBinaryExpr 评估算术和位运算表达式，如 salary * 12、age + 5、flags & 4
+//
+// 任一操作数为NULL或除数为0时结果为NULL（与MySQL一致）。
+func (e *SQLEvaluator) evaluateBinaryExpr(expr *sqlparser.BinaryExpr) (interface{}, error) {
+	leftVal, err := e.getValue(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	rightVal, err := e.getValue(expr.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	// 任一操作数为NULL时结果为NULL
+	if leftVal == nil || rightVal == nil {
+		return nil, nil
+	}
+
+	left, err := e.toExactNumber(leftVal)
+	if err != nil {
+		return nil, withNode(err, expr.Left)
+	}
+
+	right, err := e.toExactNumber(rightVal)
+	if err != nil {
+		return nil, withNode(err, expr.Right)
+	}
+
+	switch expr.Operator {
+	case sqlparser.PlusStr, sqlparser.MinusStr, sqlparser.MultStr, sqlparser.IntDivStr, sqlparser.ModStr:
+		return arithmetic(expr.Operator, left, right)
+	case sqlparser.DivStr:
+		// 除法的结果为浮点数，精确十进制模式下为十进制数值
+		if e.options.exactDecimal {
+			return decimalArithmetic(expr.Operator, left, right)
+		}
+		return arithmetic(expr.Operator, left, right)
+	case sqlparser.BitAndStr, sqlparser.BitOrStr, sqlparser.BitXorStr, sqlparser.ShiftLeftStr, sqlparser.ShiftRightStr:
+		return bitwise(expr.Operator, toBits(left), toBits(right)), nil
+	default:
+		return nil, newUnsupported(constructOperator, expr.Operator)
+	}
+}
+
+// arithmetic 执行加、减、乘、除、整除和取余运算，除数为0时结果为NULL
+//
+// 任一操作数为十进制数值时精确计算；两个整数除法以外的结果为整数；否则提升为浮点数。
+func arithmetic(operator string, left, right interface{}) (interface{}, error) {
+	switch {
+	case isDecimal(left) || isDecimal(right):
+		return decimalArithmetic(operator, left, right)
+	case isInteger(left) && isInteger(right) && operator != sqlparser.DivStr:
+		return integerArithmetic(operator, left, right)
+	default:
+		return floatArithmetic(operator, toFloat(left), toFloat(right))
+	}
+}
+
+// floatArithmetic 执行浮点数运算，除数为0时结果为NULL，整除的结果向零取整
+func floatArithmetic(operator string, l, r float64) (interface{}, error) {
+	switch operator {
+	case sqlparser.PlusStr:
+		return l + r, nil
+	case sqlparser.MinusStr:
+		return l - r, nil
+	case sqlparser.MultStr:
+		return l * r, nil
+	}
+
+	if r == 0 {
+		return nil, nil
+	}
+	switch operator {
+	case sqlparser.DivStr:
+		return l / r, nil
+	case sqlparser.IntDivStr:
+		return floatToInteger(math.Trunc(l / r))
+	default:
+		return math.Mod(l, r), nil
+	}
+}
+
+// integerArithmetic 执行整数的加、减、乘、整除和取余运算，除数为0时结果为NULL
+//
+// 两个int64的运算不溢出时直接计算，否则使用big.Int计算，结果超出64位整数范围时返回错误。
+func integerArithmetic(operator string, left, right interface{}) (interface{}, error) {
+	if l, ok := left.(int64); ok {
+		if r, ok := right.(int64); ok {
+			if (operator == sqlparser.IntDivStr || operator == sqlparser.ModStr) && r == 0 {
+				return nil, nil
+			}
+			if result, ok := int64Arithmetic(operator, l, r); ok {
+				return result, nil
+			}
+		}
+	}
+
+	l, r := toBigInt(left), toBigInt(right)
+	result := new(big.Int)
+	switch operator {
+	case sqlparser.PlusStr:
+		result.Add(l, r)
+	case sqlparser.MinusStr:
+		result.Sub(l, r)
+	case sqlparser.MultStr:
+		result.Mul(l, r)
+	default:
+		if r.Sign() == 0 {
+			return nil, nil
+		}
+		// Quo 和 Rem 向零取整，与MySQL的 DIV 和 % 一致
+		if operator == sqlparser.IntDivStr {
+			result.Quo(l, r)
+		} else {
+			result.Rem(l, r)
+		}
+	}
+	return fromBigInt(result)
+}
+
+// int64Arithmetic 执行int64运算，溢出时返回false
+func int64Arithmetic(operator string, l, r int64) (int64, bool) {
+	switch operator {
+	case sqlparser.PlusStr:
+		sum := l + r
+		return sum, (l >= 0) != (r >= 0) || (sum >= 0) == (l >= 0)
+	case sqlparser.MinusStr:
+		diff := l - r
+		return diff, (l >= 0) == (r >= 0) || (diff >= 0) == (l >= 0)
+	case sqlparser.MultStr:
+		if l == 0 || r == 0 {
+			return 0, true
+		}
+		product := l * r
+		if (l == -1 && r == math.MinInt64) || (r == -1 && l == math.MinInt64) || product/r != l {
+			return 0, false
+		}
+		return product, true
+	case sqlparser.IntDivStr:
+		if l == math.MinInt64 && r == -1 {
+			return 0, false
+		}
+		return l / r, true
+	default:
+		return l % r, true
+	}
+}
+
+// negate 取相反数，-9223372036854775808 这样的字面量在解析时为uint64，取反后为int64
+func negate(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case int64:
+		if v == math.MinInt64 {
+			return uint64(1 << 63), nil
+		}
+		return -v, nil
+	case uint64:
+		if v == 1<<63 {
+			return int64(math.MinInt64), nil
+		}
+		return -float64(v), nil
+	case float64:
+		return -v, nil
+	case *big.Rat:
+		return new(big.Rat).Neg(v), nil
+	default:
+		return nil, newConversionError(value, typeNumber, "")
+	}
+}
+
+// bitwise 执行位运算，操作数和结果都按64位无符号整数处理（与MySQL的BIGINT UNSIGNED一致）
+//
+// 结果不超过int64范围时按int64表示，否则按uint64表示，因此 ~0 和 1 << 63 为正数。
+func bitwise(operator string, l, r uint64) interface{} {
+	switch operator {
+	case sqlparser.BitAndStr:
+		return normalizeUint(l & r)
+	case sqlparser.BitOrStr:
+		return normalizeUint(l | r)
+	case sqlparser.BitXorStr:
+		return normalizeUint(l ^ r)
+	case sqlparser.ShiftLeftStr:
+		return normalizeUint(l << r)
+	default:
+		return normalizeUint(l >> r)
+	}
+}
+
+// toNumber 将值转换为参与算术运算的数值（int64、uint64、float64 或 *big.Rat）
+func toNumber(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case int64, uint64, float64, *big.Rat:
+		return v, nil
+	case bool:
+		if v {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case string:
+		// 尝试将字符串转换为数字
+		if number, ok := parseNumber(v); ok {
+			return number, nil
+		}
+		return nil, newConversionError(v, typeNumber, "")
+	default:
+		return nil, newConversionError(value, typeNumber, "")
+	}
+}
+
+// toFloat 将 toNumber 返回的数值转换为float64
+func toFloat(number interface{}) float64 {
+	switch v := number.(type) {
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case *big.Rat:
+		f, _ := v.Float64()
+		return f
+	default:
+		return number.(float64)
+	}
+}
+
+// toInt64 将 toNumber 返回的数值转换为int64，浮点数和十进制数值四舍五入，超出范围时取边界值
+func toInt64(number interface{}) int64 {
+	switch v := number.(type) {
+	case int64:
+		return v
+	case uint64:
+		return math.MaxInt64
+	default:
+		f := math.Round(toFloat(number))
+		switch {
+		case f >= math.MaxInt64:
+			return math.MaxInt64
+		case f <= math.MinInt64:
+			return math.MinInt64
+		default:
+			return int64(f)
+		}
+	}
+}
+
+// toBits 将 toNumber 返回的数值转换为参与位运算的64位无符号整数，浮点数和十进制数值四舍五入
+func toBits(number interface{}) uint64 {
+	switch v := number.(type) {
+	case int64:
+		return uint64(v)
+	case uint64:
+		return v
+	default:
+		f := math.Round(toFloat(number))
+		if f >= math.MaxUint64 {
+			return math.MaxUint64
+		}
+		if f < 0 {
+			return uint64(toInt64(f))
+		}
+		return uint64(f)
+	}
+}
+
+// floatToInteger 将整数值的浮点数转换为int64，超出范围时返回错误
+func floatToInteger(f float64) (interface{}, error) {
+	if f >= math.MinInt64 && f < math.MaxInt64 {
+		return int64(f), nil
+	}
+	return nil, newOutOfRange(f)
+}
@@ -0,0 +1,203 @@
+package sqlevaluator
+
+import "testing"
+
+func TestSQLEvaluatorArithmetic(t *testing.T) {
+	user := &User{
+		ID:       intPtr(5),
+		Name:     strPtr("张三"),
+		Age:      intPtr(25),
+		Salary:   float64Ptr(5000.50),
+		IsActive: boolPtr(true),
+	}
+	nonPtrUser := &UserWithNonPtr{
+		ID:       6,
+		Name:     "李四",
+		Age:      30,
+		Salary:   8000,
+		IsActive: false,
+	}
+	nullUser := &User{
+		ID:  intPtr(7),
+		Age: intPtr(40),
+	}
+
+	tests := []struct {
+		name        string
+		model       interface{}
+		whereClause string
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "乘法",
+			model:       user,
+			whereClause: "salary * 12 > 60000",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "加法",
+			model:       user,
+			whereClause: "age + 5 >= 30",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "减法",
+			model:       nonPtrUser,
+			whereClause: "age - 10 = 20",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "比较两侧都是算术表达式",
+			model:       user,
+			whereClause: "salary / 2 > age * 100",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "运算符优先级",
+			model:       user,
+			whereClause: "age + 5 * 2 = 35 AND (age + 5) * 2 = 60",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "整数与浮点数运算",
+			model:       user,
+			whereClause: "age + 0.5 = 25.5",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "除法结果为浮点数",
+			model:       user,
+			whereClause: "age / 2 = 12.5",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "整数除法",
+			model:       user,
+			whereClause: "age DIV 2 = 12 AND salary DIV 1000 = 5",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "取模",
+			model:       user,
+			whereClause: "age % 7 = 4 AND salary % 1000 = 0.5",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "除以0结果为NULL",
+			model:       user,
+			whereClause: "salary / 0 IS NULL AND age DIV 0 IS NULL AND age % 0 IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "除以0的比较结果为false",
+			model:       user,
+			whereClause: "age / 0 = 1",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "NULL参与运算结果为NULL",
+			model:       nullUser,
+			whereClause: "salary + 1 IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "位运算",
+			model:       user,
+			whereClause: "id & 1 = 1 AND id | 2 = 7 AND id ^ 1 = 4",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "移位运算",
+			model:       user,
+			whereClause: "id << 2 = 20 AND id >> 1 = 2",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "一元运算",
+			model:       user,
+			whereClause: "-age < 0 AND ~0 = 18446744073709551615",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "按位取反的结果为无符号整数",
+			model:       user,
+			whereClause: "~0 > 0 AND ~id > 0 AND ~0 >> 1 = 9223372036854775807",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "最高位为1的位运算结果为正数",
+			model:       user,
+			whereClause: "1 << 63 = 9223372036854775808 AND 1 << 63 > 0 AND (1 << 63 | 1) > id",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "算术表达式在右侧",
+			model:       nonPtrUser,
+			whereClause: "salary >= 250 * 32",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "算术表达式与IN和BETWEEN",
+			model:       nonPtrUser,
+			whereClause: "age * 2 IN (50, 60) AND salary / 1000 BETWEEN 7 AND 9",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "布尔值参与运算",
+			model:       nonPtrUser,
+			whereClause: "is_active + 1 = 1",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "map模型运算",
+			model:       map[string]interface{}{"used_quota": float64(30), "total_quota": float64(100)},
+			whereClause: "used_quota * 100 / total_quota < 50",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "非数值字符串参与运算",
+			model:       user,
+			whereClause: "name + 1 > 0",
+			want:        false,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(tt.model)
+			got, err := evaluator.EvaluateWhere(tt.whereClause)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package sqlevaluator
+
+import "github.com/xwb1989/sqlparser"
+
+// evaluateCaseExpr 评估CASE表达式的值
+//
+// 简单形式 CASE expr WHEN v1 THEN r1 ... 按相等比较选择分支，expr或分支值为NULL时不匹配；
+// 搜索形式 CASE WHEN cond THEN r ... 选择第一个条件为真的分支。
+// 没有匹配的分支且没有ELSE时结果为NULL（与MySQL一致）。
+func (e *SQLEvaluator) evaluateCaseExpr(expr *sqlparser.CaseExpr) (interface{}, error) {
+	var operand interface{}
+	if expr.Expr != nil {
+		value, err := e.getValue(expr.Expr)
+		if err != nil {
+			return nil, err
+		}
+		operand = value
+	}
+
+	for _, when := range expr.Whens {
+		matched, err := e.caseWhenMatched(expr.Expr != nil, operand, when.Cond)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			return e.getValue(when.Val)
+		}
+	}
+
+	if expr.Else == nil {
+		return nil, nil
+	}
+	return e.getValue(expr.Else)
+}
+
+// caseWhenMatched 判断CASE表达式的WHEN分支是否匹配
+func (e *SQLEvaluator) caseWhenMatched(simple bool, operand interface{}, cond sqlparser.Expr) (bool, error) {
+	if !simple {
+		result, err := e.evaluateExpr(cond)
+		if err != nil {
+			return false, err
+		}
+		return result == truthTrue, nil
+	}
+
+	if operand == nil {
+		return false, nil
+	}
+	value, err := e.getValue(cond)
+	if err != nil || value == nil {
+		return false, err
+	}
+	left, right, err := e.convertTypes(operand, value, sqlparser.EqualStr)
+	if err != nil {
+		return false, err
+	}
+	return valuesEqual(left, right), nil
+}
@@ -0,0 +1,118 @@
+package sqlevaluator
+
+import "testing"
+
+func TestSQLEvaluatorCaseExpr(t *testing.T) {
+	gold := map[string]interface{}{"level": "gold", "spend": 150, "age": 30}
+	silver := map[string]interface{}{"level": "silver", "spend": 300, "age": 17}
+	unknown := map[string]interface{}{"level": nil, "spend": 50, "age": nil}
+
+	tests := []struct {
+		name        string
+		model       interface{}
+		whereClause string
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "搜索形式匹配分支",
+			model:       gold,
+			whereClause: "CASE WHEN level = 'gold' THEN 100 ELSE 500 END <= spend",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "搜索形式使用ELSE",
+			model:       silver,
+			whereClause: "CASE WHEN level = 'gold' THEN 100 ELSE 500 END <= spend",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "简单形式",
+			model:       silver,
+			whereClause: "spend >= CASE level WHEN 'gold' THEN 100 WHEN 'silver' THEN 200 ELSE 500 END",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "按顺序选择第一个为真的分支",
+			model:       gold,
+			whereClause: "CASE WHEN spend > 100 THEN 'a' WHEN spend > 10 THEN 'b' END = 'a'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "没有匹配分支且没有ELSE时为NULL",
+			model:       gold,
+			whereClause: "CASE WHEN age < 18 THEN 'minor' END IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "简单形式的操作数为NULL时不匹配",
+			model:       unknown,
+			whereClause: "CASE level WHEN NULL THEN 1 ELSE 2 END = 2",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "搜索形式的条件为NULL时不匹配",
+			model:       unknown,
+			whereClause: "CASE WHEN age >= 18 THEN 'adult' WHEN age < 18 THEN 'minor' ELSE 'unknown' END = 'unknown'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "CASE参与算术运算",
+			model:       silver,
+			whereClause: "spend * CASE WHEN age < 18 THEN 0.5 ELSE 1 END = 150",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "CASE作为函数参数",
+			model:       gold,
+			whereClause: "UPPER(CASE level WHEN 'gold' THEN 'vip' END) = 'VIP'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "CASE单独作为条件",
+			model:       silver,
+			whereClause: "CASE WHEN age < 18 THEN spend > 200 ELSE false END",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "CASE与XOR组合",
+			model:       gold,
+			whereClause: "CASE WHEN spend > 100 XOR age > 18 THEN 1 ELSE 0 END = 0",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "分支中的字段不存在",
+			model:       &User{Age: intPtr(20)},
+			whereClause: "CASE WHEN age > 18 THEN missing ELSE 0 END = 1",
+			want:        false,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(tt.model)
+			got, err := evaluator.EvaluateWhere(tt.whereClause)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
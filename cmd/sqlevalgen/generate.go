@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+)
+
+// libraryPath sqlevaluator 的导入路径
+const libraryPath = "github.com/wizizm/sql-evaluator"
+
+// typeInfo 需要生成代码的结构体
+type typeInfo struct {
+	name   string
+	fields []field
+	// foreign 嵌入了其他包中的类型，整体通过反射读取
+	foreign bool
+	// exported 是否生成 GetField 和 SQLSchema 方法，嵌套的结构体只生成辅助函数
+	exported bool
+}
+
+// generate 为指定的结构体及其引用的同一包中的结构体生成代码
+func generate(pkg *packageInfo, names []string) ([]byte, error) {
+	types, err := collectTypes(pkg, names)
+	if err != nil {
+		return nil, err
+	}
+
+	needsStrings := false
+	for _, t := range types {
+		if !t.foreign {
+			needsStrings = true
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by sqlevalgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg.name)
+	fmt.Fprintf(&buf, "import (\n")
+	if needsStrings {
+		fmt.Fprintf(&buf, "\t\"strings\"\n\n")
+	}
+	fmt.Fprintf(&buf, "\tsqlevaluator %q\n)\n", libraryPath)
+
+	for _, t := range types {
+		writeSchema(&buf, t)
+		if t.exported {
+			writeMethods(&buf, t)
+		}
+		writeGetter(&buf, t)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("格式化生成的代码失败: %v", err)
+	}
+	return src, nil
+}
+
+// collectTypes 返回需要生成代码的结构体，依次为指定的结构体和它们通过字段引用的同一包中的结构体
+func collectTypes(pkg *packageInfo, names []string) ([]*typeInfo, error) {
+	var types []*typeInfo
+	seen := make(map[string]*typeInfo)
+	var add func(name string, exported bool)
+	add = func(name string, exported bool) {
+		if t, ok := seen[name]; ok {
+			t.exported = t.exported || exported
+			return
+		}
+		fields, foreign := pkg.visibleFields(name)
+		t := &typeInfo{name: name, fields: fields, foreign: foreign, exported: exported}
+		seen[name] = t
+		types = append(types, t)
+		if foreign {
+			return
+		}
+		for _, f := range fields {
+			if f.kind == kindStruct {
+				add(f.structName, false)
+			}
+		}
+	}
+
+	for _, name := range names {
+		if pkg.generic[name] {
+			return nil, fmt.Errorf("不支持带类型参数的类型: %s", name)
+		}
+		if _, ok := pkg.structs[name]; !ok {
+			return nil, fmt.Errorf("%s 不是包 %s 中的结构体类型", name, pkg.name)
+		}
+		add(name, true)
+	}
+	return types, nil
+}
+
+// schemaVar 返回列描述变量名
+func schemaVar(typeName string) string {
+	return "sqlevalSchema" + typeName
+}
+
+// getterFunc 返回读取列的辅助函数名
+func getterFunc(typeName string) string {
+	return "sqlevalGet" + typeName
+}
+
+// writeSchema 生成列描述变量
+func writeSchema(buf *bytes.Buffer, t *typeInfo) {
+	fmt.Fprintf(buf, "\n// %s %s 的列描述\n", schemaVar(t.name), t.name)
+	if t.foreign {
+		// 嵌入了其他包中的类型，字段列表通过反射获取
+		fmt.Fprintf(buf, "var %s = sqlevaluator.SchemaOf((*%s)(nil))\n", schemaVar(t.name), t.name)
+		return
+	}
+
+	fmt.Fprintf(buf, "var %s = sqlevaluator.NewSchema((*%s)(nil), []sqlevaluator.SchemaField{\n", schemaVar(t.name), t.name)
+	for _, f := range t.fields {
+		if f.tag != "" {
+			fmt.Fprintf(buf, "\t{Name: %q, Tag: %s},\n", f.name, strconv.Quote(f.tag))
+		} else {
+			fmt.Fprintf(buf, "\t{Name: %q},\n", f.name)
+		}
+	}
+	fmt.Fprintf(buf, "})\n")
+}
+
+// writeMethods 生成 GetField 和 SQLSchema 方法
+func writeMethods(buf *bytes.Buffer, t *typeInfo) {
+	fmt.Fprintf(buf, "\n// GetField 实现 sqlevaluator.FieldGetter 接口\n")
+	fmt.Fprintf(buf, "func (m *%s) GetField(name string) (interface{}, bool) {\n", t.name)
+	fmt.Fprintf(buf, "\treturn %s(m, name)\n}\n", getterFunc(t.name))
+
+	fmt.Fprintf(buf, "\n// SQLSchema 返回 %s 的列描述\n", t.name)
+	fmt.Fprintf(buf, "func (m *%s) SQLSchema() *sqlevaluator.Schema {\n", t.name)
+	fmt.Fprintf(buf, "\treturn %s\n}\n", schemaVar(t.name))
+}
+
+// writeGetter 生成读取列的辅助函数，m 为nil时仍按类型检查列名，值为NULL
+func writeGetter(buf *bytes.Buffer, t *typeInfo) {
+	fn := getterFunc(t.name)
+	fmt.Fprintf(buf, "\n// %s 读取 %s 的列，m 为nil时值为NULL\n", fn, t.name)
+	fmt.Fprintf(buf, "func %s(m *%s, name string) (interface{}, bool) {\n", fn, t.name)
+	if t.foreign {
+		fmt.Fprintf(buf, "\treturn sqlevaluator.ReflectField(m, name)\n}\n")
+		return
+	}
+
+	usesRest := false
+	for _, f := range t.fields {
+		if f.kind == kindStruct {
+			usesRest = true
+		}
+	}
+	switch {
+	case usesRest:
+		fmt.Fprintf(buf, "\tcolumn, rest, nested := strings.Cut(name, \".\")\n")
+	case len(t.fields) > 0:
+		fmt.Fprintf(buf, "\tcolumn, _, nested := strings.Cut(name, \".\")\n")
+	default:
+		fmt.Fprintf(buf, "\tcolumn, _, _ := strings.Cut(name, \".\")\n")
+	}
+
+	fmt.Fprintf(buf, "\tswitch %s.Lookup(column) {\n", schemaVar(t.name))
+	for i, f := range t.fields {
+		fmt.Fprintf(buf, "\tcase %d:\n", i)
+		writeCase(buf, f)
+	}
+	fmt.Fprintf(buf, "\t}\n\treturn nil, false\n}\n")
+}
+
+// writeCase 生成读取一个字段的分支
+func writeCase(buf *bytes.Buffer, f field) {
+	// 访问路径和路径上需要检查的nil指针
+	path := "m"
+	isNil := []string{"m == nil"}
+	notNil := []string{"m != nil"}
+	for _, step := range f.embedded {
+		path += "." + step.name
+		if step.pointer {
+			isNil = append(isNil, path+" == nil")
+			notNil = append(notNil, path+" != nil")
+		}
+	}
+	path += "." + f.name
+
+	writeValue := func(indent string) {
+		fmt.Fprintf(buf, "%sif %s {\n%s\treturn nil, true\n%s}\n", indent, strings.Join(isNil, " || "), indent, indent)
+		fmt.Fprintf(buf, "%sreturn %s, true\n", indent, path)
+	}
+
+	switch f.kind {
+	case kindLeaf:
+		fmt.Fprintf(buf, "\t\tif nested {\n\t\t\treturn nil, false\n\t\t}\n")
+		writeValue("\t\t")
+	case kindStruct:
+		fmt.Fprintf(buf, "\t\tif !nested {\n")
+		writeValue("\t\t\t")
+		fmt.Fprintf(buf, "\t\t}\n")
+		fmt.Fprintf(buf, "\t\tvar child *%s\n", f.structName)
+		fmt.Fprintf(buf, "\t\tif %s {\n", strings.Join(notNil, " && "))
+		if f.pointer {
+			fmt.Fprintf(buf, "\t\t\tchild = %s\n", path)
+		} else {
+			fmt.Fprintf(buf, "\t\t\tchild = &%s\n", path)
+		}
+		fmt.Fprintf(buf, "\t\t}\n")
+		fmt.Fprintf(buf, "\t\treturn %s(child, rest)\n", getterFunc(f.structName))
+	default:
+		fmt.Fprintf(buf, "\t\tif !nested {\n")
+		writeValue("\t\t\t")
+		fmt.Fprintf(buf, "\t\t}\n")
+		fmt.Fprintf(buf, "\t\treturn sqlevaluator.ReflectField(m, name)\n")
+	}
+}
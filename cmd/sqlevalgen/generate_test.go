@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// modelsDir 测试模型所在的目录，其中的 sqleval_gen.go 由 go generate 生成
+const modelsDir = "internal/models"
+
+func TestGenerateUpToDate(t *testing.T) {
+	pkg, err := loadPackage(modelsDir, defaultOutput)
+	if err != nil {
+		t.Fatalf("loadPackage() error = %v", err)
+	}
+
+	got, err := generate(pkg, pkg.annotated)
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	want, err := os.ReadFile(filepath.Join(modelsDir, defaultOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("生成的代码与 %s 不一致，请在 %s 中运行 go generate", defaultOutput, modelsDir)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		dir     string
+		types   string
+		wantErr bool
+	}{
+		{
+			name:    "指定的类型",
+			dir:     modelsDir,
+			types:   "Geo, Address",
+			wantErr: false,
+		},
+		{
+			name:    "不是结构体",
+			dir:     modelsDir,
+			types:   "Level",
+			wantErr: true,
+		},
+		{
+			name:    "类型不存在",
+			dir:     modelsDir,
+			types:   "Missing",
+			wantErr: true,
+		},
+		{
+			name:    "带类型参数的类型",
+			dir:     modelsDir,
+			types:   "Page",
+			wantErr: true,
+		},
+		{
+			name:    "没有Go源文件",
+			dir:     t.TempDir(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), defaultOutput)
+			err := run(tt.dir, tt.types, output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Package models sqlevalgen 的测试模型
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+//go:generate go run github.com/wizizm/sql-evaluator/cmd/sqlevalgen
+
+// Level 底层类型为字符串的命名类型
+type Level string
+
+// Audit 匿名嵌入的审计信息
+type Audit struct {
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	Version   int
+}
+
+// Geo 地理位置
+type Geo struct {
+	Zip string `json:"zip"`
+}
+
+// Address 地址
+type Address struct {
+	City string `json:"city"`
+	Geo  *Geo   `json:"geo"`
+}
+
+// Order 订单
+//
+//sqlevalgen:model
+type Order struct {
+	*Audit
+	ID         int64                  `json:"id"`
+	CustomerID int                    `json:"customer_id,omitempty"`
+	Amount     float64                `json:"amount"`
+	Discount   *float64               `json:"discount"`
+	Note       sql.NullString         `json:"note"`
+	Level      Level                  `json:"level"`
+	IsPaid     bool                   `json:"-"`
+	Shipping   Address                `json:"shipping"`
+	Billing    *Address               `json:"billing_address"`
+	Metadata   map[string]interface{} `json:"metadata"`
+	Extra      interface{}            `json:"extra"`
+	ItemCount  int
+	internal   string
+}
+
+// Left 与 Right 有同名字段，嵌入两者时该字段被隐藏
+type Left struct {
+	Code string `json:"code"`
+	Side string
+}
+
+// Right 与 Left 有同名字段
+type Right struct {
+	Code string
+}
+
+// Shipment 嵌入的结构体有同名字段
+//
+//sqlevalgen:model
+type Shipment struct {
+	Left
+	Right
+	ID int `json:"id"`
+}
+
+// Payment 嵌入了其他包中的类型，通过反射读取
+//
+//sqlevalgen:model
+type Payment struct {
+	sql.NullTime
+	ID int `json:"id"`
+}
+
+// Page 带类型参数的类型，不支持生成
+type Page[T any] struct {
+	Items []T `json:"items"`
+}
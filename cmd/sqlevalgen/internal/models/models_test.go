@@ -0,0 +1,176 @@
+package models
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	sqlevaluator "github.com/wizizm/sql-evaluator"
+)
+
+// schemaProvider 生成的 SQLSchema 方法
+type schemaProvider interface {
+	SQLSchema() *sqlevaluator.Schema
+}
+
+func TestGeneratedSchema(t *testing.T) {
+	models := []schemaProvider{&Order{}, &Shipment{}, &Payment{}}
+	for _, model := range models {
+		want := sqlevaluator.SchemaOf(model).Fields()
+		got := model.SQLSchema().Fields()
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%T.SQLSchema() = %v, want %v", model, got, want)
+		}
+	}
+}
+
+func TestGetFieldMatchesReflection(t *testing.T) {
+	discount := 0.5
+	models := map[string]sqlevaluator.FieldGetter{
+		"完整的订单": &Order{
+			Audit:      &Audit{CreatedBy: "admin", CreatedAt: time.Date(2024, 3, 21, 10, 0, 0, 0, time.UTC), Version: 3},
+			ID:         1001,
+			CustomerID: 7,
+			Amount:     99.5,
+			Discount:   &discount,
+			Note:       sql.NullString{String: "加急", Valid: true},
+			Level:      "gold",
+			IsPaid:     true,
+			Shipping:   Address{City: "Beijing", Geo: &Geo{Zip: "100000"}},
+			Billing:    &Address{City: "Shanghai"},
+			Metadata:   map[string]interface{}{"source": "app", "tags": map[string]interface{}{"vip": true}},
+			Extra:      map[string]interface{}{"channel": "web"},
+			ItemCount:  2,
+			internal:   "x",
+		},
+		"nil指针字段": &Order{ID: 1002},
+		"nil模型":   (*Order)(nil),
+		"同名字段被隐藏": &Shipment{
+			Left:  Left{Code: "L", Side: "left"},
+			Right: Right{Code: "R"},
+			ID:    1,
+		},
+		"嵌入其他包中的类型": &Payment{
+			NullTime: sql.NullTime{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+			ID:       5,
+		},
+	}
+	columns := []string{
+		"id", "ID", "Id", "customer_id", "CustomerID", "customer_id,omitempty", "amount", "discount",
+		"note", "note.string", "level", "-", "is_paid", "IsPaid", "item_count", "itemcount", "internal",
+		"audit", "created_by", "CREATED_BY", "created_at", "created_at.year", "version", "audit.version",
+		"audit.created_by", "audit.missing", "shipping.city", "shipping.geo.zip", "shipping.street",
+		"billing_address.city", "billing_address.geo.zip", "billing.city", "metadata.source",
+		"metadata.missing", "metadata.tags.vip", "extra.channel", "id.value", "unknown",
+		"code", "side", "left.code", "right.code", "left.side.x", "time", "valid", "null_time.valid",
+	}
+
+	for name, model := range models {
+		for _, column := range columns {
+			want, wantOK := sqlevaluator.ReflectField(model, column)
+			got, gotOK := model.GetField(column)
+			if gotOK != wantOK {
+				t.Errorf("%s: GetField(%q) ok = %v, want %v", name, column, gotOK, wantOK)
+				continue
+			}
+			// 按评估器的规则转换 GetField 返回的值后再比较
+			normalized, _ := sqlevaluator.ReflectField(map[string]interface{}{"v": got}, "v")
+			if !reflect.DeepEqual(normalized, want) {
+				t.Errorf("%s: GetField(%q) = %#v, want %#v", name, column, normalized, want)
+			}
+		}
+	}
+}
+
+func TestGeneratedEvaluate(t *testing.T) {
+	order := &Order{
+		Audit:    &Audit{CreatedBy: "admin"},
+		ID:       1001,
+		Amount:   99.5,
+		Level:    "gold",
+		Shipping: Address{City: "Beijing"},
+	}
+
+	tests := []struct {
+		name        string
+		whereClause string
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "顶层字段",
+			whereClause: "id = 1001 AND amount > 50 AND level = 'gold'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "嵌套和提升的字段",
+			whereClause: "shipping.city = 'Beijing' AND created_by = 'admin' AND billing_address.city IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "Null类型字段",
+			whereClause: "note IS NULL AND discount IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "字段不存在",
+			whereClause: "shipping.street = 'x'",
+			want:        false,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sqlevaluator.NewSQLEvaluator(order).EvaluateWhere(tt.whereClause)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeneratedValidate(t *testing.T) {
+	errs := sqlevaluator.Validate("shipping.city = 'Beijing' AND created_by = 'admin' AND shipping.street = 'x' AND amount LIKE '9%'", (*Order)(nil))
+	if len(errs) != 2 || errs[0].Fragment != "shipping.street" || errs[1].Fragment != "amount" {
+		t.Errorf("Validate() = %v, want errors at shipping.street and amount", errs)
+	}
+}
+
+// BenchmarkGeneratedGetField 生成的 GetField 与反射读取字段的性能对比
+func BenchmarkGeneratedGetField(b *testing.B) {
+	compiled := sqlevaluator.MustCompile("id = 1001 AND shipping.city = 'Beijing' AND created_by = 'admin'")
+	order := &Order{Audit: &Audit{CreatedBy: "admin"}, ID: 1001, Shipping: Address{City: "Beijing"}}
+
+	b.Run("GetField", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := compiled.Evaluate(order); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	// 通过map包装后评估器无法使用 GetField，只能使用反射
+	b.Run("反射", func(b *testing.B) {
+		model := map[string]interface{}{
+			"id":         order.ID,
+			"shipping":   order.Shipping,
+			"created_by": order.CreatedBy,
+		}
+		for i := 0; i < b.N; i++ {
+			if _, err := compiled.Evaluate(model); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
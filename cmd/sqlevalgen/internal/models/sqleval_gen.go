@@ -0,0 +1,416 @@
+// Code generated by sqlevalgen. DO NOT EDIT.
+
+package models
+
+import (
+	"strings"
+
+	sqlevaluator "github.com/wizizm/sql-evaluator"
+)
+
+// sqlevalSchemaOrder Order 的列描述
+var sqlevalSchemaOrder = sqlevaluator.NewSchema((*Order)(nil), []sqlevaluator.SchemaField{
+	{Name: "Audit"},
+	{Name: "CreatedBy", Tag: "created_by"},
+	{Name: "CreatedAt", Tag: "created_at"},
+	{Name: "Version"},
+	{Name: "ID", Tag: "id"},
+	{Name: "CustomerID", Tag: "customer_id,omitempty"},
+	{Name: "Amount", Tag: "amount"},
+	{Name: "Discount", Tag: "discount"},
+	{Name: "Note", Tag: "note"},
+	{Name: "Level", Tag: "level"},
+	{Name: "IsPaid", Tag: "-"},
+	{Name: "Shipping", Tag: "shipping"},
+	{Name: "Billing", Tag: "billing_address"},
+	{Name: "Metadata", Tag: "metadata"},
+	{Name: "Extra", Tag: "extra"},
+	{Name: "ItemCount"},
+})
+
+// GetField 实现 sqlevaluator.FieldGetter 接口
+func (m *Order) GetField(name string) (interface{}, bool) {
+	return sqlevalGetOrder(m, name)
+}
+
+// SQLSchema 返回 Order 的列描述
+func (m *Order) SQLSchema() *sqlevaluator.Schema {
+	return sqlevalSchemaOrder
+}
+
+// sqlevalGetOrder 读取 Order 的列，m 为nil时值为NULL
+func sqlevalGetOrder(m *Order, name string) (interface{}, bool) {
+	column, rest, nested := strings.Cut(name, ".")
+	switch sqlevalSchemaOrder.Lookup(column) {
+	case 0:
+		if !nested {
+			if m == nil {
+				return nil, true
+			}
+			return m.Audit, true
+		}
+		var child *Audit
+		if m != nil {
+			child = m.Audit
+		}
+		return sqlevalGetAudit(child, rest)
+	case 1:
+		if nested {
+			return nil, false
+		}
+		if m == nil || m.Audit == nil {
+			return nil, true
+		}
+		return m.Audit.CreatedBy, true
+	case 2:
+		if !nested {
+			if m == nil || m.Audit == nil {
+				return nil, true
+			}
+			return m.Audit.CreatedAt, true
+		}
+		return sqlevaluator.ReflectField(m, name)
+	case 3:
+		if nested {
+			return nil, false
+		}
+		if m == nil || m.Audit == nil {
+			return nil, true
+		}
+		return m.Audit.Version, true
+	case 4:
+		if nested {
+			return nil, false
+		}
+		if m == nil {
+			return nil, true
+		}
+		return m.ID, true
+	case 5:
+		if nested {
+			return nil, false
+		}
+		if m == nil {
+			return nil, true
+		}
+		return m.CustomerID, true
+	case 6:
+		if nested {
+			return nil, false
+		}
+		if m == nil {
+			return nil, true
+		}
+		return m.Amount, true
+	case 7:
+		if nested {
+			return nil, false
+		}
+		if m == nil {
+			return nil, true
+		}
+		return m.Discount, true
+	case 8:
+		if !nested {
+			if m == nil {
+				return nil, true
+			}
+			return m.Note, true
+		}
+		return sqlevaluator.ReflectField(m, name)
+	case 9:
+		if nested {
+			return nil, false
+		}
+		if m == nil {
+			return nil, true
+		}
+		return m.Level, true
+	case 10:
+		if nested {
+			return nil, false
+		}
+		if m == nil {
+			return nil, true
+		}
+		return m.IsPaid, true
+	case 11:
+		if !nested {
+			if m == nil {
+				return nil, true
+			}
+			return m.Shipping, true
+		}
+		var child *Address
+		if m != nil {
+			child = &m.Shipping
+		}
+		return sqlevalGetAddress(child, rest)
+	case 12:
+		if !nested {
+			if m == nil {
+				return nil, true
+			}
+			return m.Billing, true
+		}
+		var child *Address
+		if m != nil {
+			child = m.Billing
+		}
+		return sqlevalGetAddress(child, rest)
+	case 13:
+		if !nested {
+			if m == nil {
+				return nil, true
+			}
+			return m.Metadata, true
+		}
+		return sqlevaluator.ReflectField(m, name)
+	case 14:
+		if !nested {
+			if m == nil {
+				return nil, true
+			}
+			return m.Extra, true
+		}
+		return sqlevaluator.ReflectField(m, name)
+	case 15:
+		if nested {
+			return nil, false
+		}
+		if m == nil {
+			return nil, true
+		}
+		return m.ItemCount, true
+	}
+	return nil, false
+}
+
+// sqlevalSchemaAudit Audit 的列描述
+var sqlevalSchemaAudit = sqlevaluator.NewSchema((*Audit)(nil), []sqlevaluator.SchemaField{
+	{Name: "CreatedBy", Tag: "created_by"},
+	{Name: "CreatedAt", Tag: "created_at"},
+	{Name: "Version"},
+})
+
+// sqlevalGetAudit 读取 Audit 的列，m 为nil时值为NULL
+func sqlevalGetAudit(m *Audit, name string) (interface{}, bool) {
+	column, _, nested := strings.Cut(name, ".")
+	switch sqlevalSchemaAudit.Lookup(column) {
+	case 0:
+		if nested {
+			return nil, false
+		}
+		if m == nil {
+			return nil, true
+		}
+		return m.CreatedBy, true
+	case 1:
+		if !nested {
+			if m == nil {
+				return nil, true
+			}
+			return m.CreatedAt, true
+		}
+		return sqlevaluator.ReflectField(m, name)
+	case 2:
+		if nested {
+			return nil, false
+		}
+		if m == nil {
+			return nil, true
+		}
+		return m.Version, true
+	}
+	return nil, false
+}
+
+// sqlevalSchemaAddress Address 的列描述
+var sqlevalSchemaAddress = sqlevaluator.NewSchema((*Address)(nil), []sqlevaluator.SchemaField{
+	{Name: "City", Tag: "city"},
+	{Name: "Geo", Tag: "geo"},
+})
+
+// sqlevalGetAddress 读取 Address 的列，m 为nil时值为NULL
+func sqlevalGetAddress(m *Address, name string) (interface{}, bool) {
+	column, rest, nested := strings.Cut(name, ".")
+	switch sqlevalSchemaAddress.Lookup(column) {
+	case 0:
+		if nested {
+			return nil, false
+		}
+		if m == nil {
+			return nil, true
+		}
+		return m.City, true
+	case 1:
+		if !nested {
+			if m == nil {
+				return nil, true
+			}
+			return m.Geo, true
+		}
+		var child *Geo
+		if m != nil {
+			child = m.Geo
+		}
+		return sqlevalGetGeo(child, rest)
+	}
+	return nil, false
+}
+
+// sqlevalSchemaGeo Geo 的列描述
+var sqlevalSchemaGeo = sqlevaluator.NewSchema((*Geo)(nil), []sqlevaluator.SchemaField{
+	{Name: "Zip", Tag: "zip"},
+})
+
+// sqlevalGetGeo 读取 Geo 的列，m 为nil时值为NULL
+func sqlevalGetGeo(m *Geo, name string) (interface{}, bool) {
+	column, _, nested := strings.Cut(name, ".")
+	switch sqlevalSchemaGeo.Lookup(column) {
+	case 0:
+		if nested {
+			return nil, false
+		}
+		if m == nil {
+			return nil, true
+		}
+		return m.Zip, true
+	}
+	return nil, false
+}
+
+// sqlevalSchemaShipment Shipment 的列描述
+var sqlevalSchemaShipment = sqlevaluator.NewSchema((*Shipment)(nil), []sqlevaluator.SchemaField{
+	{Name: "Left"},
+	{Name: "Side"},
+	{Name: "Right"},
+	{Name: "ID", Tag: "id"},
+})
+
+// GetField 实现 sqlevaluator.FieldGetter 接口
+func (m *Shipment) GetField(name string) (interface{}, bool) {
+	return sqlevalGetShipment(m, name)
+}
+
+// SQLSchema 返回 Shipment 的列描述
+func (m *Shipment) SQLSchema() *sqlevaluator.Schema {
+	return sqlevalSchemaShipment
+}
+
+// sqlevalGetShipment 读取 Shipment 的列，m 为nil时值为NULL
+func sqlevalGetShipment(m *Shipment, name string) (interface{}, bool) {
+	column, rest, nested := strings.Cut(name, ".")
+	switch sqlevalSchemaShipment.Lookup(column) {
+	case 0:
+		if !nested {
+			if m == nil {
+				return nil, true
+			}
+			return m.Left, true
+		}
+		var child *Left
+		if m != nil {
+			child = &m.Left
+		}
+		return sqlevalGetLeft(child, rest)
+	case 1:
+		if nested {
+			return nil, false
+		}
+		if m == nil {
+			return nil, true
+		}
+		return m.Left.Side, true
+	case 2:
+		if !nested {
+			if m == nil {
+				return nil, true
+			}
+			return m.Right, true
+		}
+		var child *Right
+		if m != nil {
+			child = &m.Right
+		}
+		return sqlevalGetRight(child, rest)
+	case 3:
+		if nested {
+			return nil, false
+		}
+		if m == nil {
+			return nil, true
+		}
+		return m.ID, true
+	}
+	return nil, false
+}
+
+// sqlevalSchemaLeft Left 的列描述
+var sqlevalSchemaLeft = sqlevaluator.NewSchema((*Left)(nil), []sqlevaluator.SchemaField{
+	{Name: "Code", Tag: "code"},
+	{Name: "Side"},
+})
+
+// sqlevalGetLeft 读取 Left 的列，m 为nil时值为NULL
+func sqlevalGetLeft(m *Left, name string) (interface{}, bool) {
+	column, _, nested := strings.Cut(name, ".")
+	switch sqlevalSchemaLeft.Lookup(column) {
+	case 0:
+		if nested {
+			return nil, false
+		}
+		if m == nil {
+			return nil, true
+		}
+		return m.Code, true
+	case 1:
+		if nested {
+			return nil, false
+		}
+		if m == nil {
+			return nil, true
+		}
+		return m.Side, true
+	}
+	return nil, false
+}
+
+// sqlevalSchemaRight Right 的列描述
+var sqlevalSchemaRight = sqlevaluator.NewSchema((*Right)(nil), []sqlevaluator.SchemaField{
+	{Name: "Code"},
+})
+
+// sqlevalGetRight 读取 Right 的列，m 为nil时值为NULL
+func sqlevalGetRight(m *Right, name string) (interface{}, bool) {
+	column, _, nested := strings.Cut(name, ".")
+	switch sqlevalSchemaRight.Lookup(column) {
+	case 0:
+		if nested {
+			return nil, false
+		}
+		if m == nil {
+			return nil, true
+		}
+		return m.Code, true
+	}
+	return nil, false
+}
+
+// sqlevalSchemaPayment Payment 的列描述
+var sqlevalSchemaPayment = sqlevaluator.SchemaOf((*Payment)(nil))
+
+// GetField 实现 sqlevaluator.FieldGetter 接口
+func (m *Payment) GetField(name string) (interface{}, bool) {
+	return sqlevalGetPayment(m, name)
+}
+
+// SQLSchema 返回 Payment 的列描述
+func (m *Payment) SQLSchema() *sqlevaluator.Schema {
+	return sqlevalSchemaPayment
+}
+
+// sqlevalGetPayment 读取 Payment 的列，m 为nil时值为NULL
+func sqlevalGetPayment(m *Payment, name string) (interface{}, bool) {
+	return sqlevaluator.ReflectField(m, name)
+}
@@ -0,0 +1,70 @@
+// sqlevalgen 为结构体生成 sqlevaluator.FieldGetter 的实现和列描述，评估时不再通过反射读取字段
+//
+// 在结构体的注释中添加 //sqlevalgen:model，或者通过 -type 指定类型，然后在包中添加：
+//
+//	//go:generate go run github.com/wizizm/sql-evaluator/cmd/sqlevalgen
+//
+// 生成的 GetField 按与评估器相同的规则匹配列名（json 标签、字段名、下划线命名），支持匿名嵌入结构体提升的字段
+// 和同一包中嵌套结构体的限定列名（如 address.city）；路径上的nil指针视为NULL。map、接口和其他包中的结构体
+// 等无法静态解析的字段通过 sqlevaluator.ReflectField 读取。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultOutput 默认的输出文件名
+const defaultOutput = "sqleval_gen.go"
+
+func main() {
+	typeNames := flag.String("type", "", "以逗号分隔的类型名，为空时生成注释中带有 //sqlevalgen:model 的结构体")
+	output := flag.String("output", "", "输出文件，默认为包目录下的 "+defaultOutput)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "用法: sqlevalgen [-type T1,T2] [-output file] [包目录]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	if err := run(dir, *typeNames, *output); err != nil {
+		fmt.Fprintf(os.Stderr, "sqlevalgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run 解析包目录并写入生成的代码
+func run(dir, typeNames, output string) error {
+	if output == "" {
+		output = filepath.Join(dir, defaultOutput)
+	}
+
+	pkg, err := loadPackage(dir, filepath.Base(output))
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	if typeNames != "" {
+		for _, name := range strings.Split(typeNames, ",") {
+			names = append(names, strings.TrimSpace(name))
+		}
+	} else {
+		names = pkg.annotated
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("%s 中没有需要生成的类型，请在结构体注释中添加 //%s 或使用 -type", dir, annotation)
+	}
+
+	src, err := generate(pkg, names)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, src, 0o644)
+}
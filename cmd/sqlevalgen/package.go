@@ -0,0 +1,326 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// annotation 标记需要生成代码的结构体的注释
+const annotation = "sqlevalgen:model"
+
+// basicTypes 预声明的基本类型，这类字段不能访问子字段
+var basicTypes = map[string]bool{
+	"bool": true, "string": true, "byte": true, "rune": true, "uintptr": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true, "complex64": true, "complex128": true,
+}
+
+// packageInfo 解析得到的包信息
+type packageInfo struct {
+	name string
+	// structs 包中的结构体类型，包括底层类型为同一包中结构体的命名类型
+	structs map[string]*ast.StructType
+	// named 包中其他命名类型的类型表达式
+	named map[string]ast.Expr
+	// generic 带类型参数的类型，不支持生成
+	generic map[string]bool
+	// annotated 注释中带有 //sqlevalgen:model 的结构体，按声明顺序排列
+	annotated []string
+}
+
+// loadPackage 解析目录中参与构建的非测试文件，skip 为生成的输出文件名
+func loadPackage(dir, skip string) (*packageInfo, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &packageInfo{
+		structs: make(map[string]*ast.StructType),
+		named:   make(map[string]ast.Expr),
+		generic: make(map[string]bool),
+	}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		base := filepath.Base(file)
+		if base == skip || strings.HasSuffix(base, "_test.go") {
+			continue
+		}
+		// 忽略构建约束不满足的文件
+		if ok, err := build.Default.MatchFile(dir, base); err != nil || !ok {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if pkg.name == "" {
+			pkg.name = f.Name.Name
+		} else if pkg.name != f.Name.Name {
+			return nil, fmt.Errorf("%s 中包含多个包: %s 和 %s", dir, pkg.name, f.Name.Name)
+		}
+		pkg.collectTypes(f)
+	}
+	if pkg.name == "" {
+		return nil, fmt.Errorf("%s 中没有Go源文件", dir)
+	}
+
+	pkg.resolveNamedStructs()
+	return pkg, nil
+}
+
+// collectTypes 收集文件中的类型声明
+func (p *packageInfo) collectTypes(f *ast.File) {
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			name := typeSpec.Name.Name
+			if typeSpec.TypeParams != nil {
+				p.generic[name] = true
+				continue
+			}
+
+			if st, ok := typeSpec.Type.(*ast.StructType); ok {
+				p.structs[name] = st
+			} else {
+				p.named[name] = typeSpec.Type
+			}
+
+			doc := typeSpec.Doc
+			if doc == nil && len(gen.Specs) == 1 {
+				doc = gen.Doc
+			}
+			if hasAnnotation(doc) {
+				p.annotated = append(p.annotated, name)
+			}
+		}
+	}
+}
+
+// hasAnnotation 判断注释中是否有 //sqlevalgen:model
+func hasAnnotation(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, comment := range doc.List {
+		if strings.TrimSpace(strings.TrimPrefix(comment.Text, "//")) == annotation {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveNamedStructs 将底层类型为同一包中结构体的命名类型（如 type Admin User）加入 structs
+func (p *packageInfo) resolveNamedStructs() {
+	for changed := true; changed; {
+		changed = false
+		for name, expr := range p.named {
+			ident, ok := expr.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			if st, ok := p.structs[ident.Name]; ok {
+				p.structs[name] = st
+				delete(p.named, name)
+				changed = true
+			}
+		}
+	}
+}
+
+// fieldKind 字段的类型分类，决定如何访问它的子字段
+type fieldKind int
+
+const (
+	// kindLeaf 基本类型或底层类型为基本类型的字段，没有子字段
+	kindLeaf fieldKind = iota
+	// kindStruct 同一包中的结构体或结构体指针，子字段由生成的代码读取
+	kindStruct
+	// kindDynamic map、接口、其他包中的类型等，子字段通过反射读取
+	kindDynamic
+)
+
+// embedStep 访问提升字段时经过的匿名嵌入字段
+type embedStep struct {
+	name    string
+	pointer bool
+}
+
+// field 结构体中可以作为列访问的字段
+type field struct {
+	name     string
+	tag      string
+	embedded []embedStep
+	depth    int
+	kind     fieldKind
+	// structName kindStruct 字段的结构体类型名
+	structName string
+	// pointer kindStruct 字段是否为结构体指针
+	pointer bool
+}
+
+// visibleFields 返回结构体的导出字段，顺序和提升规则与 reflect.VisibleFields 一致
+//
+// 结构体嵌入了其他包中的类型时无法确定提升的字段，foreign 为true。
+func (p *packageInfo) visibleFields(typeName string) (fields []field, foreign bool) {
+	all, foreign := p.collectFields(typeName, 0, nil, map[string]bool{typeName: true})
+
+	// 同名字段中只有深度最小且唯一的字段可见
+	minDepth := make(map[string]int)
+	count := make(map[string]int)
+	for _, f := range all {
+		depth, ok := minDepth[f.name]
+		switch {
+		case !ok || f.depth < depth:
+			minDepth[f.name] = f.depth
+			count[f.name] = 1
+		case f.depth == depth:
+			count[f.name]++
+		}
+	}
+
+	for _, f := range all {
+		if f.depth == minDepth[f.name] && count[f.name] == 1 && ast.IsExported(f.name) {
+			fields = append(fields, f)
+		}
+	}
+	return fields, foreign
+}
+
+// collectFields 按声明顺序收集结构体的字段，匿名嵌入结构体的字段紧跟在嵌入字段之后
+func (p *packageInfo) collectFields(typeName string, depth int, embedded []embedStep, visiting map[string]bool) ([]field, bool) {
+	var fields []field
+	foreign := false
+	for _, astField := range p.structs[typeName].Fields.List {
+		tag := jsonTag(astField.Tag)
+		kind, structName, pointer := p.classify(astField.Type)
+
+		if len(astField.Names) > 0 {
+			for _, name := range astField.Names {
+				fields = append(fields, field{
+					name:       name.Name,
+					tag:        tag,
+					embedded:   embedded,
+					depth:      depth,
+					kind:       kind,
+					structName: structName,
+					pointer:    pointer,
+				})
+			}
+			continue
+		}
+
+		// 匿名嵌入字段
+		name, local := p.embeddedName(astField.Type)
+		fields = append(fields, field{
+			name:       name,
+			tag:        tag,
+			embedded:   embedded,
+			depth:      depth,
+			kind:       kind,
+			structName: structName,
+			pointer:    pointer,
+		})
+		if !local {
+			foreign = true
+			continue
+		}
+		if kind != kindStruct || visiting[structName] {
+			continue
+		}
+
+		visiting[structName] = true
+		steps := append(append([]embedStep(nil), embedded...), embedStep{name: name, pointer: pointer})
+		promoted, subForeign := p.collectFields(structName, depth+1, steps, visiting)
+		delete(visiting, structName)
+		fields = append(fields, promoted...)
+		foreign = foreign || subForeign
+	}
+	return fields, foreign
+}
+
+// embeddedName 返回匿名嵌入字段的字段名，local 表示类型是否在同一包中声明
+func (p *packageInfo) embeddedName(expr ast.Expr) (name string, local bool) {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch t := expr.(type) {
+	case *ast.Ident:
+		// 预声明类型（如 string）没有子字段，视为同一包中的类型
+		return t.Name, true
+	case *ast.SelectorExpr:
+		return t.Sel.Name, false
+	case *ast.IndexExpr:
+		name, _ := p.embeddedName(t.X)
+		return name, false
+	case *ast.IndexListExpr:
+		name, _ := p.embeddedName(t.X)
+		return name, false
+	default:
+		return "", false
+	}
+}
+
+// classify 返回字段类型的分类
+func (p *packageInfo) classify(expr ast.Expr) (kind fieldKind, structName string, pointer bool) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if _, ok := p.structs[t.Name]; ok {
+			return kindStruct, t.Name, false
+		}
+		if p.isLeaf(t, 0) {
+			return kindLeaf, "", false
+		}
+	case *ast.StarExpr:
+		if ident, ok := t.X.(*ast.Ident); ok {
+			if _, ok := p.structs[ident.Name]; ok {
+				return kindStruct, ident.Name, true
+			}
+		}
+		// 基本类型的指针，nil时为NULL
+		if p.isLeaf(t.X, 0) {
+			return kindLeaf, "", false
+		}
+	}
+	return kindDynamic, "", false
+}
+
+// isLeaf 判断类型是否为基本类型或底层类型为基本类型的命名类型
+func (p *packageInfo) isLeaf(expr ast.Expr, depth int) bool {
+	ident, ok := expr.(*ast.Ident)
+	if !ok || depth > len(p.named) {
+		return false
+	}
+	if basicTypes[ident.Name] {
+		return true
+	}
+	if underlying, ok := p.named[ident.Name]; ok {
+		return p.isLeaf(underlying, depth+1)
+	}
+	return false
+}
+
+// jsonTag 返回字段标签中的 json 标签
+func jsonTag(lit *ast.BasicLit) string {
+	if lit == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(tag).Get("json")
+}
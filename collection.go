@@ -0,0 +1,194 @@
+package sqlevaluator
+
+import "reflect"
+
+// ElementError 评估切片中某个元素时发生的错误
+type ElementError struct {
+	// Index 出错元素在切片中的下标
+	Index int
+	// Err 评估该元素时返回的错误
+	Err  error
+	lang Language
+}
+
+// Error 实现error接口，按编译子句时设置的语言返回错误信息
+func (e *ElementError) Error() string {
+	return e.lang.text(msgElement, e.Index, e.lang.cause(e.Err))
+}
+
+// Unwrap 返回评估元素时的原始错误
+func (e *ElementError) Unwrap() error {
+	return e.Err
+}
+
+// Filter 返回满足WHERE子句的元素，保持原有顺序
+//
+// 子句只编译一次，args 按顺序绑定子句中的 ? 占位符。评估某个元素出错时返回 *ElementError。
+// 需要三值逻辑、时区等配置时先通过 Compile 编译子句，再使用 FilterCompiled。
+func Filter[T any](items []T, where string, args ...interface{}) ([]T, error) {
+	compiled, err := Compile(where)
+	if err != nil {
+		return nil, err
+	}
+	return FilterCompiled(items, compiled, args...)
+}
+
+// FilterCompiled 与 Filter 相同，使用已编译的WHERE子句及其配置
+func FilterCompiled[T any](items []T, where *CompiledWhere, args ...interface{}) ([]T, error) {
+	result := make([]T, 0)
+	err := matchEach(items, where, args, func(i int, matched bool) bool {
+		if matched {
+			result = append(result, items[i])
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Partition 将元素按是否满足WHERE子句分为两组，保持原有顺序
+func Partition[T any](items []T, where string, args ...interface{}) (matched []T, unmatched []T, err error) {
+	compiled, err := Compile(where)
+	if err != nil {
+		return nil, nil, err
+	}
+	return PartitionCompiled(items, compiled, args...)
+}
+
+// PartitionCompiled 与 Partition 相同，使用已编译的WHERE子句及其配置
+func PartitionCompiled[T any](items []T, where *CompiledWhere, args ...interface{}) (matched []T, unmatched []T, err error) {
+	matched = make([]T, 0)
+	unmatched = make([]T, 0)
+	err = matchEach(items, where, args, func(i int, ok bool) bool {
+		if ok {
+			matched = append(matched, items[i])
+		} else {
+			unmatched = append(unmatched, items[i])
+		}
+		return true
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return matched, unmatched, nil
+}
+
+// FindFirst 返回第一个满足WHERE子句的元素及其下标，没有满足条件的元素时下标为-1
+func FindFirst[T any](items []T, where string, args ...interface{}) (T, int, error) {
+	compiled, err := Compile(where)
+	if err != nil {
+		var zero T
+		return zero, -1, err
+	}
+	return FindFirstCompiled(items, compiled, args...)
+}
+
+// FindFirstCompiled 与 FindFirst 相同，使用已编译的WHERE子句及其配置
+func FindFirstCompiled[T any](items []T, where *CompiledWhere, args ...interface{}) (T, int, error) {
+	var zero T
+	index := -1
+	err := matchEach(items, where, args, func(i int, matched bool) bool {
+		if matched {
+			index = i
+			return false
+		}
+		return true
+	})
+	if err != nil || index < 0 {
+		return zero, -1, err
+	}
+	return items[index], index, nil
+}
+
+// Count 返回满足WHERE子句的元素个数
+func Count[T any](items []T, where string, args ...interface{}) (int, error) {
+	compiled, err := Compile(where)
+	if err != nil {
+		return 0, err
+	}
+	return CountCompiled(items, compiled, args...)
+}
+
+// CountCompiled 与 Count 相同，使用已编译的WHERE子句及其配置
+func CountCompiled[T any](items []T, where *CompiledWhere, args ...interface{}) (int, error) {
+	count := 0
+	err := matchEach(items, where, args, func(i int, matched bool) bool {
+		if matched {
+			count++
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Any 判断是否存在满足WHERE子句的元素，找到后立即停止评估
+func Any[T any](items []T, where string, args ...interface{}) (bool, error) {
+	_, index, err := FindFirst(items, where, args...)
+	return index >= 0, err
+}
+
+// AnyCompiled 与 Any 相同，使用已编译的WHERE子句及其配置
+func AnyCompiled[T any](items []T, where *CompiledWhere, args ...interface{}) (bool, error) {
+	_, index, err := FindFirstCompiled(items, where, args...)
+	return index >= 0, err
+}
+
+// All 判断是否所有元素都满足WHERE子句，遇到不满足的元素后立即停止评估；空切片返回true
+func All[T any](items []T, where string, args ...interface{}) (bool, error) {
+	compiled, err := Compile(where)
+	if err != nil {
+		return false, err
+	}
+	return AllCompiled(items, compiled, args...)
+}
+
+// AllCompiled 与 All 相同，使用已编译的WHERE子句及其配置
+func AllCompiled[T any](items []T, where *CompiledWhere, args ...interface{}) (bool, error) {
+	all := true
+	err := matchEach(items, where, args, func(i int, matched bool) bool {
+		all = matched
+		return matched
+	})
+	if err != nil {
+		return false, err
+	}
+	return all, nil
+}
+
+// matchEach 依次评估每个元素，visit 返回false时停止遍历
+func matchEach[T any](items []T, compiled *CompiledWhere, args []interface{}, visit func(i int, matched bool) bool) error {
+	params, err := positionalParams(args)
+	if err != nil {
+		return compiled.resolveError(err)
+	}
+
+	// 元素是接口或本身实现了 FieldGetter 时直接传入元素，否则传入元素的指针，避免复制结构体
+	elemType := reflect.TypeOf((*T)(nil)).Elem()
+	byValue := elemType.Kind() == reflect.Interface || elemType.Implements(fieldGetterInterface)
+
+	e := &SQLEvaluator{
+		options: compiled.options,
+		params:  params,
+		args:    len(args),
+	}
+	for i := range items {
+		if byValue {
+			e.model = items[i]
+		} else {
+			e.model = &items[i]
+		}
+		matched, err := e.evaluateCompiled(compiled)
+		if err != nil {
+			return &ElementError{Index: i, Err: err, lang: compiled.options.language}
+		}
+		if !visit(i, matched) {
+			return nil
+		}
+	}
+	return nil
+}
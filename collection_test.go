@@ -0,0 +1,226 @@
+package sqlevaluator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func collectionUsers() []UserWithNonPtr {
+	return []UserWithNonPtr{
+		{ID: 1, Name: "张三", Age: 25, Salary: 5000, IsActive: true},
+		{ID: 2, Name: "李四", Age: 17, Salary: 0, IsActive: false},
+		{ID: 3, Name: "王五", Age: 32, Salary: 8000, IsActive: true},
+		{ID: 4, Name: "赵六", Age: 40, Salary: 3000, IsActive: false},
+	}
+}
+
+func userIDs(users []UserWithNonPtr) []int {
+	ids := make([]int, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name        string
+		whereClause string
+		args        []interface{}
+		want        []int
+		wantErr     bool
+	}{
+		{
+			name:        "部分匹配",
+			whereClause: "age >= 18 AND is_active = true",
+			want:        []int{1, 3},
+		},
+		{
+			name:        "全部不匹配",
+			whereClause: "age > 100",
+			want:        []int{},
+		},
+		{
+			name:        "位置参数",
+			whereClause: "salary > ? AND id IN ?",
+			args:        []interface{}{2000, []int{1, 2, 4}},
+			want:        []int{1, 4},
+		},
+		{
+			name:        "语法错误",
+			whereClause: "age >",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Filter(collectionUsers(), tt.whereClause, tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Filter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(userIDs(got), tt.want) {
+				t.Errorf("Filter() = %v, want %v", userIDs(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectionHelpers(t *testing.T) {
+	users := collectionUsers()
+
+	matched, unmatched, err := Partition(users, "is_active = true")
+	if err != nil {
+		t.Fatalf("Partition() error = %v", err)
+	}
+	if !reflect.DeepEqual(userIDs(matched), []int{1, 3}) || !reflect.DeepEqual(userIDs(unmatched), []int{2, 4}) {
+		t.Errorf("Partition() = %v, %v", userIDs(matched), userIDs(unmatched))
+	}
+
+	first, index, err := FindFirst(users, "age > 30")
+	if err != nil || index != 2 || first.ID != 3 {
+		t.Errorf("FindFirst() = %v, %d, %v, want ID 3 at index 2", first.ID, index, err)
+	}
+	_, index, err = FindFirst(users, "age > 100")
+	if err != nil || index != -1 {
+		t.Errorf("FindFirst() 无匹配 index = %d, err = %v, want -1", index, err)
+	}
+
+	count, err := Count(users, "salary BETWEEN ? AND ?", 3000, 6000)
+	if err != nil || count != 2 {
+		t.Errorf("Count() = %d, %v, want 2", count, err)
+	}
+
+	any, err := Any(users, "name = '王五'")
+	if err != nil || !any {
+		t.Errorf("Any() = %v, %v, want true", any, err)
+	}
+	all, err := All(users, "age > 18")
+	if err != nil || all {
+		t.Errorf("All() = %v, %v, want false", all, err)
+	}
+	all, err = All([]UserWithNonPtr{}, "age > 18")
+	if err != nil || !all {
+		t.Errorf("All() 空切片 = %v, %v, want true", all, err)
+	}
+
+	// 指针元素和map元素
+	ptrUsers := []*UserWithNonPtr{&users[0], &users[1]}
+	if count, err := Count(ptrUsers, "age < 18"); err != nil || count != 1 {
+		t.Errorf("Count() 指针元素 = %d, %v, want 1", count, err)
+	}
+	rows := []map[string]interface{}{{"age": 20}, {"age": 15}}
+	if count, err := Count(rows, "age >= 18"); err != nil || count != 1 {
+		t.Errorf("Count() map元素 = %d, %v, want 1", count, err)
+	}
+}
+
+func TestCollectionFieldGetter(t *testing.T) {
+	age := 30
+	getters := []FieldGetter{&Profile{id: 1, age: &age}, &Profile{id: 2}}
+	got, err := Filter(getters, "age > 5")
+	if err != nil || len(got) != 1 || got[0].(*Profile).id != 1 {
+		t.Errorf("Filter() 接口元素 = %v, %v, want id 1", got, err)
+	}
+
+	profiles := []*Profile{{id: 1, age: &age}, {id: 2}}
+	if count, err := Count(profiles, "age IS NULL"); err != nil || count != 1 {
+		t.Errorf("Count() FieldGetter元素 = %d, %v, want 1", count, err)
+	}
+
+	var models []interface{}
+	for _, user := range collectionUsers() {
+		models = append(models, user)
+	}
+	if count, err := Count(models, "is_active = true"); err != nil || count != 2 {
+		t.Errorf("Count() interface{}元素 = %d, %v, want 2", count, err)
+	}
+}
+
+func TestCollectionCompiled(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": 1, "level": "gold"},
+		{"id": 2, "level": nil},
+		{"id": 3, "level": "silver"},
+	}
+	whereClause := "NOT (level IN ('gold', NULL))"
+
+	// 默认模式下与NULL比较为false，NOT 后为true
+	if count, err := Count(rows, whereClause); err != nil || count != 2 {
+		t.Errorf("Count() = %d, %v, want 2", count, err)
+	}
+
+	// 三值逻辑模式下 NOT (x IN (..., NULL)) 不会为真
+	compiled := MustCompile(whereClause, WithThreeValuedLogic())
+	if count, err := CountCompiled(rows, compiled); err != nil || count != 0 {
+		t.Errorf("CountCompiled() = %d, %v, want 0", count, err)
+	}
+	matched, unmatched, err := PartitionCompiled(rows, compiled)
+	if err != nil || len(matched) != 0 || len(unmatched) != 3 {
+		t.Errorf("PartitionCompiled() = %v, %v, %v", matched, unmatched, err)
+	}
+
+	users := collectionUsers()
+	strict := MustCompile("age < ? OR agee > 1", WithStrictTypeCheck())
+	if _, err := FilterCompiled(users, strict, 100); err == nil {
+		t.Error("FilterCompiled() 严格模式 error = nil, want error")
+	}
+
+	adults := MustCompile("age >= ?")
+	if got, err := FilterCompiled(users, adults, 18); err != nil || !reflect.DeepEqual(userIDs(got), []int{1, 3, 4}) {
+		t.Errorf("FilterCompiled() = %v, %v", userIDs(got), err)
+	}
+	if first, index, err := FindFirstCompiled(users, adults, 30); err != nil || index != 2 || first.ID != 3 {
+		t.Errorf("FindFirstCompiled() = %v, %d, %v", first.ID, index, err)
+	}
+	if any, err := AnyCompiled(users, adults, 50); err != nil || any {
+		t.Errorf("AnyCompiled() = %v, %v, want false", any, err)
+	}
+	if all, err := AllCompiled(users, adults, 10); err != nil || !all {
+		t.Errorf("AllCompiled() = %v, %v, want true", all, err)
+	}
+}
+
+func TestCollectionElementError(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"name": "张三", "age": 20},
+		{"name": "李四", "age": 30},
+		{"name": "王五", "age": "未知"},
+		{"name": "赵六", "age": 15},
+	}
+
+	_, err := Filter(rows, "age + 1 > 18")
+	var elemErr *ElementError
+	if !errors.As(err, &elemErr) {
+		t.Fatalf("Filter() error = %v, want *ElementError", err)
+	}
+	if elemErr.Index != 2 {
+		t.Errorf("ElementError.Index = %d, want 2", elemErr.Index)
+	}
+	if elemErr.Unwrap() == nil {
+		t.Error("ElementError.Unwrap() = nil")
+	}
+
+	// 找到匹配元素后不再评估后续元素
+	_, index, err := FindFirst(rows, "age + 1 > 18")
+	if err != nil || index != 0 {
+		t.Errorf("FindFirst() = %d, %v, want 0", index, err)
+	}
+}
+
+func BenchmarkFilter(b *testing.B) {
+	users := make([]*User, 1000)
+	for i := range users {
+		users[i] = randomUser()
+	}
+	whereClause := "(age > 25 AND salary BETWEEN 1000 AND 5000) OR name LIKE '张%'"
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Filter(users, whereClause); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
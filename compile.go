@@ -0,0 +1,222 @@
+package sqlevaluator
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sync"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// CompiledWhere 预编译的WHERE子句
+//
+// 编译结果只包含解析后的语法树、评估配置和预编译的模式，评估时不会修改它们，因此可以在多个goroutine之间共享。
+type CompiledWhere struct {
+	clause  string
+	expr    sqlparser.Expr
+	params  []string
+	options options
+	// placeholders 子句中 ? 占位符的个数
+	placeholders int
+	// functions 子句引用的自定义函数，键为小写的函数名
+	functions map[string]*userFunction
+	// likePatterns 模式为字面量的LIKE表达式编译后的模式
+	likePatterns map[*sqlparser.ComparisonExpr]*likePattern
+	// regexps 模式为字面量的REGEXP表达式编译后的正则表达式
+	regexps map[*sqlparser.ComparisonExpr]*regexp.Regexp
+	// locator 语法树节点在子句中的位置，只在第一次报告错误或记录评估过程时计算
+	locator     *clauseLocator
+	locatorOnce sync.Once
+	// typeChecks 严格模式下各模型类型或列描述的校验结果，值为 typeCheckResult
+	typeChecks sync.Map
+}
+
+// typeCheckResult 严格模式下一种模型类型的校验结果，err 为nil表示校验通过
+type typeCheckResult struct {
+	err error
+}
+
+// Compile 解析WHERE子句，返回可对多个模型重复评估的编译结果
+//
+// 子句中的函数在编译时解析，自定义函数需要在编译前通过RegisterFunction注册。列与模型相关，在评估时解析，
+// 结构体字段的解析结果按模型类型缓存，同一类型的同一列只在第一次评估时解析。
+func Compile(whereClause string, opts ...Option) (*CompiledWhere, error) {
+	return compile(whereClause, newOptions(opts), nil)
+}
+
+// compile 解析WHERE子句，local 为评估器注册的自定义函数
+func compile(whereClause string, opts options, local map[string]*userFunction) (*CompiledWhere, error) {
+	expr, err := parseWhere(whereClause)
+	if err != nil {
+		return nil, resolveError(err, nil, opts.language)
+	}
+
+	compiled := &CompiledWhere{
+		clause:  whereClause,
+		options: opts,
+	}
+	if expr != nil {
+		compiled.expr = expr
+		if err := compiled.prepare(local); err != nil {
+			return nil, compiled.resolveError(err)
+		}
+	}
+	return compiled, nil
+}
+
+// prepare 解析子句引用的函数并预编译字面量模式
+func (c *CompiledWhere) prepare(local map[string]*userFunction) error {
+	var err error
+	c.params = collectParams(c.expr)
+	c.placeholders = countPlaceholders(c.params)
+	c.functions, err = resolveFunctions(c.expr, local, c.options)
+	if err != nil {
+		return err
+	}
+	c.likePatterns, err = compileLikePatterns(c.expr, c.options)
+	if err != nil {
+		return err
+	}
+	c.regexps, err = compileRegexps(c.expr, c.options)
+	return err
+}
+
+// resolveError 为结构化错误计算在子句中的位置并设置配置的语言
+func (c *CompiledWhere) resolveError(err error) error {
+	if _, ok := err.(SQLError); !ok {
+		return err
+	}
+	return resolveError(err, c.nodeLocator(), c.options.language)
+}
+
+// nodeLocator 返回语法树节点在子句中的位置，第一次调用时计算
+func (c *CompiledWhere) nodeLocator() *clauseLocator {
+	c.locatorOnce.Do(func() {
+		c.locator = locateNodes(c.clause, c.expr)
+	})
+	return c.locator
+}
+
+// wherePrefix 解析时拼接在WHERE子句之前的语句
+const wherePrefix = "SELECT * FROM `users` WHERE "
+
+// parseWhere 解析WHERE子句，子句为空时返回nil
+func parseWhere(whereClause string) (sqlparser.Expr, error) {
+	// 解析SQL，XOR运算等sqlparser不支持的语法需要先改写
+	stmt, err := sqlparser.Parse(wherePrefix + rewriteClause(whereClause))
+	if err != nil {
+		return nil, newParseError(whereClause, err)
+	}
+
+	selectStmt, ok := stmt.(*sqlparser.Select)
+	if !ok {
+		return nil, newUnsupported(constructStatement, fmt.Sprintf("%T", stmt))
+	}
+	if selectStmt.Where == nil {
+		return nil, nil
+	}
+	return selectStmt.Where.Expr, nil
+}
+
+// MustCompile 与Compile相同，但解析失败时panic，适用于初始化全局规则
+func MustCompile(whereClause string, opts ...Option) *CompiledWhere {
+	compiled, err := Compile(whereClause, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return compiled
+}
+
+// Evaluate 使用给定模型评估已编译的WHERE子句
+//
+// args 按顺序绑定子句中的 ? 占位符，切片参数在IN列表中展开，如 `id IN ?`。
+func (c *CompiledWhere) Evaluate(model interface{}, args ...interface{}) (bool, error) {
+	bound, err := positionalParams(args)
+	if err != nil {
+		return false, c.resolveError(err)
+	}
+	e := &SQLEvaluator{
+		model:   model,
+		options: c.options,
+		params:  bound,
+		args:    len(args),
+	}
+	return e.evaluateCompiled(c)
+}
+
+// EvaluateNamed 使用给定模型和命名参数评估已编译的WHERE子句
+//
+// params 绑定子句中的 :name 占位符，参数名可以带或不带前导冒号。
+func (c *CompiledWhere) EvaluateNamed(model interface{}, params map[string]interface{}) (bool, error) {
+	bound, err := namedParams(params)
+	if err != nil {
+		return false, c.resolveError(err)
+	}
+	e := &SQLEvaluator{
+		model:   model,
+		options: c.options,
+		params:  bound,
+	}
+	return e.evaluateCompiled(c)
+}
+
+// String 返回编译前的WHERE子句
+func (c *CompiledWhere) String() string {
+	return c.clause
+}
+
+// evaluateCompiled 评估已编译的WHERE子句
+//
+// e 必须是本次评估专用的评估器，评估期间会记录编译结果。
+func (e *SQLEvaluator) evaluateCompiled(c *CompiledWhere) (bool, error) {
+	if err := c.checkParams(e.params, e.args); err != nil {
+		return false, c.resolveError(err)
+	}
+	if c.expr == nil {
+		return true, nil
+	}
+	e.compiled = c
+	if c.options.strictTypeCheck {
+		if err := c.typeCheck(e.model); err != nil {
+			return false, err
+		}
+	}
+	result, err := e.evaluateExpr(c.expr)
+	if err != nil {
+		return false, c.resolveError(err)
+	}
+	// UNKNOWN 在最终结果中视为false
+	return result == truthTrue, nil
+}
+
+// typeCheck 严格模式下按模型类型校验整个子句，直接校验编译时解析的语法树
+//
+// 结果按模型类型缓存，实现了 SchemaProvider 的模型按列描述缓存；只实现了 FieldGetter 的模型按 GetField
+// 返回的值校验，同一类型的不同实例结果可能不同，因此不缓存，每次评估都遍历一次语法树。
+func (c *CompiledWhere) typeCheck(model interface{}) error {
+	var key interface{}
+	switch m := model.(type) {
+	case SchemaProvider:
+		key = m.SQLSchema()
+	case FieldGetter:
+	default:
+		key = reflect.TypeOf(model)
+	}
+	if key != nil {
+		if cached, ok := c.typeChecks.Load(key); ok {
+			return cached.(typeCheckResult).err
+		}
+	}
+
+	var err error
+	if columns, resolverErr := newColumnResolver(model); resolverErr != nil {
+		err = ValidationErrors{newValidationError(c.resolveError(resolverErr), c.options.language)}
+	} else if errs := validateExpr(c.expr, c.nodeLocator(), columns, c.options, c.functions); errs != nil {
+		err = errs
+	}
+	if key != nil {
+		c.typeChecks.Store(key, typeCheckResult{err: err})
+	}
+	return err
+}
@@ -0,0 +1,211 @@
+package sqlevaluator
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name        string
+		whereClause string
+		wantErr     bool
+	}{
+		{
+			name:        "简单条件",
+			whereClause: "name = '张三' AND age > 20",
+			wantErr:     false,
+		},
+		{
+			name:        "复杂条件",
+			whereClause: "(age > 25 AND salary BETWEEN 1000 AND 5000) OR name LIKE '张%'",
+			wantErr:     false,
+		},
+		{
+			name:        "语法错误",
+			whereClause: "name = AND",
+			wantErr:     true,
+		},
+		{
+			name:        "空条件",
+			whereClause: "",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiled, err := Compile(tt.whereClause)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Compile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && compiled.String() != tt.whereClause {
+				t.Errorf("String() = %v, want %v", compiled.String(), tt.whereClause)
+			}
+		})
+	}
+}
+
+func TestCompiledWhereEvaluate(t *testing.T) {
+	compiled, err := Compile("name = '张三' AND age > 20")
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		model   interface{}
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "指针模型匹配",
+			model: &User{
+				ID:       intPtr(1),
+				Name:     strPtr("张三"),
+				Age:      intPtr(25),
+				Salary:   float64Ptr(5000.50),
+				IsActive: boolPtr(true),
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "指针模型不匹配",
+			model: &User{
+				ID:       intPtr(2),
+				Name:     strPtr("李四"),
+				Age:      intPtr(25),
+				Salary:   float64Ptr(5000.50),
+				IsActive: boolPtr(true),
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "非指针模型匹配",
+			model: &UserWithNonPtr{
+				ID:       1,
+				Name:     "张三",
+				Age:      30,
+				Salary:   5000.50,
+				IsActive: true,
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "NULL字段",
+			model: &User{
+				ID:  intPtr(3),
+				Age: intPtr(30),
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name:    "字段不存在",
+			model:   &struct{ Title string }{Title: "x"},
+			want:    false,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := compiled.Evaluate(tt.model)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCompiledWhereConcurrent 验证同一编译结果可在多个goroutine中并发评估
+func TestCompiledWhereConcurrent(t *testing.T) {
+	compiled := MustCompile("(age > 30 AND salary > 5000) OR (name LIKE 'a%' AND is_active = true) AND id IN (1, 2, 3)")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed))
+			for j := 0; j < 200; j++ {
+				age := r.Intn(100)
+				user := &User{
+					ID:       intPtr(r.Intn(5)),
+					Name:     strPtr("abc"),
+					Age:      &age,
+					Salary:   float64Ptr(r.Float64() * 10000),
+					IsActive: boolPtr(r.Intn(2) == 0),
+				}
+				got, err := compiled.Evaluate(user)
+				if err != nil {
+					errs <- err
+					return
+				}
+				want, _ := NewSQLEvaluator(user).EvaluateWhere(compiled.String())
+				if got != want {
+					t.Errorf("Evaluate() = %v, EvaluateWhere() = %v", got, want)
+					return
+				}
+			}
+		}(int64(i))
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Evaluate() error = %v", err)
+	}
+}
+
+func TestMustCompilePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustCompile() 未对非法子句panic")
+		}
+	}()
+	MustCompile("name = AND")
+}
+
+// BenchmarkCompiledWhere 预编译条件性能测试
+func BenchmarkCompiledWhere(b *testing.B) {
+	// 初始化随机数生成器
+	rand.Seed(time.Now().UnixNano())
+
+	// 预编译与BenchmarkSQLEvaluatorComplex相同的查询条件
+	complexQueries := []*CompiledWhere{
+		MustCompile("(age > 30 AND salary > 5000) OR (name LIKE 'a%' AND is_active = true) AND id > 100"),
+		MustCompile("(name IS NOT NULL AND age BETWEEN 20 AND 50) OR (salary > 8000 AND is_active = true) AND id IN (1, 2, 3, 4, 5)"),
+		MustCompile("(age > 25 AND salary BETWEEN 3000 AND 7000) AND (name LIKE 'b%' OR is_active = true) AND id > 50"),
+		MustCompile("(salary > 6000 AND is_active = true) OR (age > 35 AND name LIKE 'c%') AND id BETWEEN 1 AND 100"),
+	}
+
+	// 运行基准测试
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// 生成随机用户数据
+		user := randomUser()
+
+		// 随机选择一个预编译条件
+		compiled := complexQueries[rand.Intn(len(complexQueries))]
+
+		// 执行查询
+		_, err := compiled.Evaluate(user)
+		if err != nil {
+			b.Fatalf("预编译查询执行失败: %v", err)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package sqlevaluator
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// Decimal 精确十进制数值需要实现的接口
+//
+// 实现了该接口的字段和参数（如 github.com/shopspring/decimal 的 decimal.Decimal）按 *big.Rat 精确比较和运算，
+// 不会转换为float64。Rat 返回nil时视为NULL。
+type Decimal interface {
+	Rat() *big.Rat
+}
+
+var (
+	decimalInterface = reflect.TypeOf((*Decimal)(nil)).Elem()
+	ratType          = reflect.TypeOf((*big.Rat)(nil))
+)
+
+// normalizeDecimal 将 *big.Rat 或实现了 Decimal 接口的值转换为 *big.Rat，不是十进制数值时返回false
+//
+// 返回的 *big.Rat 是副本，评估过程不会修改字段的值。
+func normalizeDecimal(value reflect.Value) (interface{}, bool) {
+	var rat *big.Rat
+	if value.IsValid() && value.Type() == ratType {
+		rat = value.Interface().(*big.Rat)
+	} else if decimal, ok := asInterface(value, decimalInterface); ok {
+		if value.Kind() == reflect.Ptr && value.IsNil() {
+			return nil, true
+		}
+		rat = decimal.(Decimal).Rat()
+	} else {
+		return nil, false
+	}
+
+	if rat == nil {
+		return nil, true
+	}
+	return new(big.Rat).Set(rat), true
+}
+
+// isDecimal 判断值是否为十进制数值
+func isDecimal(value interface{}) bool {
+	_, ok := value.(*big.Rat)
+	return ok
+}
+
+// floatToRat 按float64的最短十进制表示转换为 *big.Rat，使 0.1 转换为 1/10；NaN和无穷大返回nil
+func floatToRat(f float64) *big.Rat {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil
+	}
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	return r
+}
+
+// toRat 将有限的数值转换为 *big.Rat，浮点数按最短十进制表示转换
+func toRat(number interface{}) *big.Rat {
+	switch v := number.(type) {
+	case int64:
+		return new(big.Rat).SetInt64(v)
+	case uint64:
+		return new(big.Rat).SetUint64(v)
+	case float64:
+		return floatToRat(v)
+	default:
+		return number.(*big.Rat)
+	}
+}
+
+// compareDecimal 比较十进制数值与其他数值，NaN小于其他任何数值
+func compareDecimal(a, b interface{}) int {
+	if f, ok := a.(float64); ok && (math.IsNaN(f) || math.IsInf(f, 0)) {
+		return compareFloat64(f, 0)
+	}
+	if f, ok := b.(float64); ok && (math.IsNaN(f) || math.IsInf(f, 0)) {
+		return compareFloat64(0, f)
+	}
+	return toRat(a).Cmp(toRat(b))
+}
+
+// decimalArithmetic 精确执行十进制数值的加、减、乘、除、整除和取余运算，除数为0时结果为NULL
+//
+// 整除和取余向零取整，整除的结果为整数。
+func decimalArithmetic(operator string, left, right interface{}) (interface{}, error) {
+	l, r := toRat(left), toRat(right)
+	if l == nil || r == nil {
+		// NaN和无穷大无法精确表示，按浮点数计算
+		return floatArithmetic(operator, toFloat(left), toFloat(right))
+	}
+
+	result := new(big.Rat)
+	switch operator {
+	case sqlparser.PlusStr:
+		return result.Add(l, r), nil
+	case sqlparser.MinusStr:
+		return result.Sub(l, r), nil
+	case sqlparser.MultStr:
+		return result.Mul(l, r), nil
+	}
+
+	if r.Sign() == 0 {
+		return nil, nil
+	}
+	result.Quo(l, r)
+	switch operator {
+	case sqlparser.DivStr:
+		return result, nil
+	case sqlparser.IntDivStr:
+		return fromBigInt(truncRat(result))
+	default:
+		// l - trunc(l/r) * r
+		quotient := new(big.Rat).SetInt(truncRat(result))
+		return result.Sub(l, quotient.Mul(quotient, r)), nil
+	}
+}
+
+// truncRat 向零取整
+func truncRat(r *big.Rat) *big.Int {
+	return new(big.Int).Quo(r.Num(), r.Denom())
+}
+
+// floorRat 向下取整
+func floorRat(r *big.Rat) *big.Int {
+	i := truncRat(r)
+	if r.Sign() < 0 && !r.IsInt() {
+		i.Sub(i, big.NewInt(1))
+	}
+	return i
+}
+
+// ceilRat 向上取整
+func ceilRat(r *big.Rat) *big.Int {
+	i := truncRat(r)
+	if r.Sign() > 0 && !r.IsInt() {
+		i.Add(i, big.NewInt(1))
+	}
+	return i
+}
+
+// roundRat 四舍五入到digits位小数，digits为负数时对整数部分取整，0.5向远离零的方向舍入
+func roundRat(r *big.Rat, digits int) *big.Rat {
+	abs := digits
+	if abs < 0 {
+		abs = -abs
+	}
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs)), nil))
+
+	scaled := new(big.Rat).Set(r)
+	if digits >= 0 {
+		scaled.Mul(scaled, scale)
+	} else {
+		scaled.Quo(scaled, scale)
+	}
+
+	// 绝对值加0.5后向零取整
+	half := big.NewRat(int64(r.Sign()), 2)
+	rounded := new(big.Rat).SetInt(truncRat(scaled.Add(scaled, half)))
+	if digits >= 0 {
+		return rounded.Quo(rounded, scale)
+	}
+	return rounded.Mul(rounded, scale)
+}
+
+// ratString 将十进制数值转换为字符串，有限小数完整输出，无限小数按float64的精度输出
+func ratString(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+
+	// 分母只含因子2和5时为有限小数，小数位数为两者指数的较大值
+	denom := new(big.Int).Set(r.Denom())
+	digits := 0
+	for _, factor := range []int64{2, 5} {
+		count := 0
+		f := big.NewInt(factor)
+		remainder := new(big.Int)
+		for {
+			quotient, rem := new(big.Int).QuoRem(denom, f, remainder)
+			if rem.Sign() != 0 {
+				break
+			}
+			denom = quotient
+			count++
+		}
+		if count > digits {
+			digits = count
+		}
+	}
+	if denom.Cmp(big.NewInt(1)) == 0 {
+		return r.FloatString(digits)
+	}
+
+	f, _ := r.Float64()
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// exactNumber 在精确十进制模式下将有限的float64转换为 *big.Rat，其他值不变
+func (e *SQLEvaluator) exactNumber(value interface{}) interface{} {
+	if !e.options.exactDecimal {
+		return value
+	}
+	if f, ok := value.(float64); ok {
+		if r := floatToRat(f); r != nil {
+			return r
+		}
+	}
+	return value
+}
+
+// parseExactNumber 与 parseNumber 相同，精确十进制模式下非整数的字符串精确解析为 *big.Rat
+func (e *SQLEvaluator) parseExactNumber(s string) (interface{}, bool) {
+	number, ok := parseNumber(s)
+	if !ok || !e.options.exactDecimal || isInteger(number) {
+		return number, ok
+	}
+	// big.Rat 还接受 1/3 这样的分数，只解析 parseNumber 认可的字符串
+	if r, ok := new(big.Rat).SetString(s); ok {
+		return r, true
+	}
+	return e.exactNumber(number), true
+}
+
+// toExactNumber 与 toNumber 相同，精确十进制模式下浮点数和非整数的数值字符串转换为 *big.Rat
+func (e *SQLEvaluator) toExactNumber(value interface{}) (interface{}, error) {
+	if s, ok := value.(string); ok {
+		if number, ok := e.parseExactNumber(s); ok {
+			return number, nil
+		}
+	}
+	number, err := toNumber(value)
+	if err != nil {
+		return nil, err
+	}
+	return e.exactNumber(number), nil
+}
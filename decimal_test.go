@@ -0,0 +1,243 @@
+package sqlevaluator
+
+import (
+	"math/big"
+	"testing"
+)
+
+// Cents 以分为单位存储金额的十进制类型
+type Cents int64
+
+// Rat 实现 Decimal 接口
+func (c Cents) Rat() *big.Rat {
+	return big.NewRat(int64(c), 100)
+}
+
+// Invoice 十进制测试使用的模型
+type Invoice struct {
+	ID       int64    `json:"id"`
+	Total    Cents    `json:"total"`
+	Discount *Cents   `json:"discount"`
+	Price    float64  `json:"price"`
+	Rate     *big.Rat `json:"rate"`
+	Amount   string   `json:"amount"`
+}
+
+func TestSQLEvaluatorExactDecimal(t *testing.T) {
+	invoice := &Invoice{
+		ID:     1001,
+		Total:  30,
+		Price:  0.3,
+		Rate:   big.NewRat(13, 100),
+		Amount: "19.99",
+	}
+
+	tests := []struct {
+		name        string
+		whereClause string
+		args        []interface{}
+		opts        []Option
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "默认模式下浮点数相加有误差",
+			whereClause: "0.1 + 0.2 = 0.3",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "精确模式下浮点数相加",
+			whereClause: "0.1 + 0.2 = 0.3",
+			opts:        []Option{WithExactDecimal()},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "精确模式下超出uint64范围的整数字面量",
+			whereClause: "id < 18446744073709551616 AND 18446744073709551616 - 1 = 18446744073709551615",
+			opts:        []Option{WithExactDecimal()},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "精确模式下浮点数字段参与运算",
+			whereClause: "price = 0.1 + 0.2 AND price * 3 = 0.9",
+			opts:        []Option{WithExactDecimal()},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "默认模式下Decimal字段与浮点运算结果比较",
+			whereClause: "total = 0.3 AND total = 0.1 + 0.2",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "Decimal字段与小数字面量比较",
+			whereClause: "total = 0.3 AND total > 0.29 AND total <= 0.3",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "Decimal字段精确运算",
+			whereClause: "total * 3 = 0.9 AND total / 3 = 0.1 AND total - 0.1 = 0.2",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "Decimal字段的BETWEEN边界",
+			whereClause: "total BETWEEN 0.3 AND 1",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "Decimal字段在IN列表中",
+			whereClause: "total IN (0.1, 0.3)",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "nil的Decimal指针视为NULL",
+			whereClause: "discount IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "big.Rat字段",
+			whereClause: "rate = 0.13 AND total * rate = 0.039",
+			opts:        []Option{WithExactDecimal()},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "精确模式下数值字符串",
+			whereClause: "amount = 19.99 AND amount + 0.01 = 20",
+			opts:        []Option{WithExactDecimal()},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "精确模式下超过float64精度的字符串",
+			whereClause: "'0.30000000000000000001' > 0.3",
+			opts:        []Option{WithExactDecimal()},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "精确模式下的除法",
+			whereClause: "1 / 3 * 3 = 1 AND 10 / 4 = 2.5",
+			opts:        []Option{WithExactDecimal()},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "精确模式下除数为0",
+			whereClause: "total / 0 IS NULL AND total % 0 IS NULL",
+			opts:        []Option{WithExactDecimal()},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "精确模式下的整除和取余",
+			whereClause: "7.5 DIV 2 = 3 AND 7.5 % 2 = 1.5 AND -7.5 % 2 = -1.5",
+			opts:        []Option{WithExactDecimal()},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "精确模式下的参数",
+			whereClause: "price + ? = 0.4",
+			args:        []interface{}{0.1},
+			opts:        []Option{WithExactDecimal()},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "Decimal参数",
+			whereClause: "total = ?",
+			args:        []interface{}{Cents(30)},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "ROUND、CEIL和FLOOR",
+			whereClause: "ROUND(2.675, 2) = 2.68 AND ROUND(-2.5) = -3 AND CEIL(total) = 1 AND FLOOR(-0.3) = -1",
+			opts:        []Option{WithExactDecimal()},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "十进制数值转换为字符串",
+			whereClause: "CONCAT(total, '/', 1 / 4) = '0.3/0.25' AND ABS(-total) = total",
+			opts:        []Option{WithExactDecimal()},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "精确模式下整数保持整数",
+			whereClause: "id = 1001 AND id + 1 = 1002",
+			opts:        []Option{WithExactDecimal()},
+			want:        true,
+			wantErr:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(invoice, tt.opts...)
+			got, err := evaluator.EvaluateWhere(tt.whereClause, tt.args...)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundRat(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  *big.Rat
+		digits int
+		want   string
+	}{
+		{
+			name:   "保留两位小数",
+			value:  big.NewRat(2675, 1000),
+			digits: 2,
+			want:   "2.68",
+		},
+		{
+			name:   "负数向远离零的方向舍入",
+			value:  big.NewRat(-2675, 1000),
+			digits: 2,
+			want:   "-2.68",
+		},
+		{
+			name:   "对整数部分取整",
+			value:  big.NewRat(1250, 1),
+			digits: -2,
+			want:   "1300",
+		},
+		{
+			name:   "无限小数",
+			value:  big.NewRat(1, 3),
+			digits: 4,
+			want:   "0.3333",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ratString(roundRat(tt.value, tt.digits)); got != tt.want {
+				t.Errorf("roundRat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,517 @@
+package sqlevaluator
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// 结构化错误的错误码，不随错误信息的语言变化，可以直接用于API响应
+const (
+	CodeParseError      = "PARSE_ERROR"
+	CodeFieldNotFound   = "FIELD_NOT_FOUND"
+	CodeTypeMismatch    = "TYPE_MISMATCH"
+	CodeUnsupportedExpr = "UNSUPPORTED_EXPR"
+	CodeParamError      = "PARAM_ERROR"
+	CodeInvalidArgument = "INVALID_ARGUMENT"
+	CodeFunctionError   = "FUNCTION_ERROR"
+)
+
+// SQLError 评估器返回的结构化错误，可以通过 errors.As 获取
+//
+// 具体类型为 *ParseError、*FieldNotFoundError、*TypeMismatchError、*UnsupportedExprError、
+// *ParamError、*InvalidArgumentError 或 *FunctionError。
+type SQLError interface {
+	error
+	// Code 返回错误码，如 FIELD_NOT_FOUND
+	Code() string
+	// Position 返回出错片段在子句中的字节偏移（无法定位时为-1）和原始文本
+	Position() (pos int, fragment string)
+}
+
+// Location 错误在WHERE子句中的位置
+type Location struct {
+	// Pos 出错片段在子句中的字节偏移，从0开始，无法定位时为-1
+	Pos int
+	// Fragment 出错的SQL片段，如 `agee` 或 `is_active > 'abc'`
+	Fragment string
+}
+
+// Position 返回出错片段的位置和原始文本
+func (l Location) Position() (int, string) {
+	return l.Pos, l.Fragment
+}
+
+// noLocation 尚未定位的位置
+var noLocation = Location{Pos: -1}
+
+// errorContext 结构化错误在定位前关联的语法树节点和显示语言
+type errorContext struct {
+	node sqlparser.SQLNode
+	lang Language
+}
+
+// locatedError 评估器内部用于定位和本地化结构化错误的接口
+type locatedError interface {
+	SQLError
+	// context 返回错误的定位信息
+	context() *errorContext
+	// located 返回设置了位置、节点和语言的副本，不修改可能被缓存共享的原错误
+	located(loc Location, ctx errorContext) locatedError
+}
+
+// withNode 为尚未关联节点的结构化错误关联产生错误的语法树节点，其他错误原样返回
+//
+// 评估自内向外返回错误，因此关联的是产生错误的最内层节点。
+func withNode(err error, node sqlparser.SQLNode) error {
+	typed, ok := err.(locatedError)
+	if !ok || typed.context().node != nil {
+		return err
+	}
+	pos, fragment := typed.Position()
+	ctx := *typed.context()
+	ctx.node = node
+	return typed.located(Location{Pos: pos, Fragment: fragment}, ctx)
+}
+
+// resolveError 按关联的节点计算结构化错误的位置并设置语言，loc 为nil时只设置语言
+func resolveError(err error, loc *clauseLocator, lang Language) error {
+	typed, ok := err.(locatedError)
+	if !ok {
+		return err
+	}
+	pos, fragment := typed.Position()
+	ctx := *typed.context()
+	ctx.lang = lang
+	if pos < 0 && loc != nil && ctx.node != nil {
+		pos, fragment = loc.position(ctx.node)
+	}
+	return typed.located(Location{Pos: pos, Fragment: fragment}, ctx)
+}
+
+// ParseError WHERE子句存在语法错误
+type ParseError struct {
+	Location
+	// Message 解析器给出的错误描述，不含位置
+	Message string
+	ctx     errorContext
+}
+
+// Code 返回错误码 PARSE_ERROR
+func (e *ParseError) Code() string {
+	return CodeParseError
+}
+
+// Error 返回本地化的错误信息
+func (e *ParseError) Error() string {
+	return e.ctx.lang.locate(e.ctx.lang.text(msgParse, e.Message), e.Location)
+}
+
+func (e *ParseError) context() *errorContext {
+	return &e.ctx
+}
+
+func (e *ParseError) located(loc Location, ctx errorContext) locatedError {
+	copied := *e
+	copied.Location, copied.ctx = loc, ctx
+	return &copied
+}
+
+// syntaxPositionPattern sqlparser语法错误信息中的位置
+var syntaxPositionPattern = regexp.MustCompile(`^(.*?) at position (\d+)`)
+
+// newParseError 将sqlparser的解析错误转换为 *ParseError
+//
+// sqlparser报告的位置是出错的词法单元之后的偏移，且包含拼接的SELECT语句，这里换算为原始子句中出错的词法单元；
+// 子句不完整时位置为子句的长度。子句在解析前被改写时无法定位，保留解析器的原始信息。
+func newParseError(whereClause string, err error) *ParseError {
+	parseErr := &ParseError{Location: noLocation, Message: err.Error()}
+	match := syntaxPositionPattern.FindStringSubmatch(err.Error())
+	if match == nil || rewriteClause(whereClause) != whereClause {
+		return parseErr
+	}
+
+	position, _ := strconv.Atoi(match[2])
+	end := position - len(wherePrefix) - 1
+	if end >= len(whereClause) {
+		parseErr.Pos = len(whereClause)
+	} else {
+		tokens, _ := scanTokens(whereClause)
+		for _, token := range tokens {
+			if token.end >= end {
+				parseErr.Pos = token.start
+				parseErr.Fragment = token.text(whereClause)
+				break
+			}
+		}
+	}
+	if parseErr.Pos >= 0 {
+		parseErr.Message = match[1]
+	}
+	return parseErr
+}
+
+// FieldNotFoundError 列无法解析为模型中的字段
+type FieldNotFoundError struct {
+	Location
+	// Field SQL中的列名，限定列名以点连接，如 address.street
+	Field string
+	// Parent 不为空时表示路径上的该字段不是结构体或map，无法访问其子字段
+	Parent string
+	ctx    errorContext
+}
+
+// newFieldNotFound 创建字段未找到错误
+func newFieldNotFound(field string) *FieldNotFoundError {
+	return &FieldNotFoundError{Location: noLocation, Field: field}
+}
+
+// newNotContainer 创建无法访问子字段的错误，segments 为列名的各级，第i级的父字段不是结构体或map
+func newNotContainer(segments []string, i int) *FieldNotFoundError {
+	return &FieldNotFoundError{
+		Location: noLocation,
+		Field:    strings.Join(segments, "."),
+		Parent:   strings.Join(segments[:i], "."),
+	}
+}
+
+// Code 返回错误码 FIELD_NOT_FOUND
+func (e *FieldNotFoundError) Code() string {
+	return CodeFieldNotFound
+}
+
+// Error 返回本地化的错误信息
+func (e *FieldNotFoundError) Error() string {
+	lang := e.ctx.lang
+	if e.Parent == "" {
+		return lang.locate(lang.text(msgFieldNotFound, e.Field), e.Location)
+	}
+	child := strings.SplitN(strings.TrimPrefix(e.Field, e.Parent+"."), ".", 2)[0]
+	return lang.locate(lang.text(msgNotContainer, e.Parent, child), e.Location)
+}
+
+func (e *FieldNotFoundError) context() *errorContext {
+	return &e.ctx
+}
+
+func (e *FieldNotFoundError) located(loc Location, ctx errorContext) locatedError {
+	copied := *e
+	copied.Location, copied.ctx = loc, ctx
+	return &copied
+}
+
+// 类型不匹配错误中的SQL类型名，其他类型使用Go类型名
+const (
+	typeNumber = "number"
+	typeString = "string"
+	typeBool   = "bool"
+	typeTime   = "time"
+	typeNull   = "null"
+)
+
+// TypeMismatchError 操作数的类型无法转换为操作符或函数需要的类型
+type TypeMismatchError struct {
+	Location
+	// Left、Right 两侧的类型：number、string、bool、time 或Go类型名；
+	// 值无法转换为所需类型时 Right 为所需的类型
+	Left, Right string
+	// Operator 比较或匹配操作符，如 > 或 LIKE，类型转换失败时为空
+	Operator string
+	ctx      errorContext
+}
+
+// newTypeMismatch 创建两个值之间的类型不匹配错误
+func newTypeMismatch(left, right interface{}, operator string) *TypeMismatchError {
+	return &TypeMismatchError{Location: noLocation, Left: typeName(left), Right: typeName(right), Operator: operator}
+}
+
+// newConversionError 创建值无法转换为所需类型的错误，target 为 typeNumber 等类型名
+func newConversionError(value interface{}, target, operator string) *TypeMismatchError {
+	return &TypeMismatchError{Location: noLocation, Left: typeName(value), Right: target, Operator: operator}
+}
+
+// typeName 返回值在类型不匹配错误中的类型名
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return typeNull
+	case int64, uint64, float64, *big.Rat:
+		return typeNumber
+	case string:
+		return typeString
+	case bool:
+		return typeBool
+	case time.Time:
+		return typeTime
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// Code 返回错误码 TYPE_MISMATCH
+func (e *TypeMismatchError) Code() string {
+	return CodeTypeMismatch
+}
+
+// Error 返回本地化的错误信息
+func (e *TypeMismatchError) Error() string {
+	lang := e.ctx.lang
+	left, right := lang.name(e.Left), lang.name(e.Right)
+	if e.Operator == "" {
+		return lang.locate(lang.text(msgTypeMismatch, left, right), e.Location)
+	}
+	return lang.locate(lang.text(msgTypeMismatchOp, left, strings.ToUpper(e.Operator), right), e.Location)
+}
+
+func (e *TypeMismatchError) context() *errorContext {
+	return &e.ctx
+}
+
+func (e *TypeMismatchError) located(loc Location, ctx errorContext) locatedError {
+	copied := *e
+	copied.Location, copied.ctx = loc, ctx
+	return &copied
+}
+
+// 不支持的语法结构，用于 UnsupportedExprError.Construct
+const (
+	constructExpression = "expression"
+	constructOperator   = "operator"
+	constructFunction   = "function"
+	constructArgument   = "function argument"
+	constructValue      = "value"
+	constructMapKey     = "map key type"
+	constructStatement  = "statement"
+)
+
+// UnsupportedExprError 评估器不支持的表达式、操作符或函数
+type UnsupportedExprError struct {
+	Location
+	// Construct 不支持的语法结构：expression、operator、function、function argument、value、map key type 或 statement
+	Construct string
+	// Name 不支持的表达式类型、操作符或函数名
+	Name string
+	ctx  errorContext
+}
+
+// newUnsupported 创建不支持的语法结构错误
+func newUnsupported(construct, name string) *UnsupportedExprError {
+	return &UnsupportedExprError{Location: noLocation, Construct: construct, Name: name}
+}
+
+// newUnsupportedExpr 创建不支持的表达式类型错误，name 为语法树节点的类型名
+func newUnsupportedExpr(expr sqlparser.SQLNode) *UnsupportedExprError {
+	err := newUnsupported(constructExpression, fmt.Sprintf("%T", expr))
+	err.ctx.node = expr
+	return err
+}
+
+// Code 返回错误码 UNSUPPORTED_EXPR
+func (e *UnsupportedExprError) Code() string {
+	return CodeUnsupportedExpr
+}
+
+// Error 返回本地化的错误信息
+func (e *UnsupportedExprError) Error() string {
+	lang := e.ctx.lang
+	return lang.locate(lang.text(msgUnsupported, lang.name(e.Construct), e.Name), e.Location)
+}
+
+func (e *UnsupportedExprError) context() *errorContext {
+	return &e.ctx
+}
+
+func (e *UnsupportedExprError) located(loc Location, ctx errorContext) locatedError {
+	copied := *e
+	copied.Location, copied.ctx = loc, ctx
+	return &copied
+}
+
+// 参数错误的原因，用于 ParamError.Reason
+const (
+	paramUnbound = "unbound"
+	paramInvalid = "invalid"
+	paramList    = "list"
+	paramExtra   = "extra"
+)
+
+// ParamError 占位符参数未绑定、无法转换或用法错误
+type ParamError struct {
+	Location
+	// Param 参数名，命名参数为 :name，位置参数为 ?
+	Param string
+	// Index 位置参数是第几个 ?，从1开始，命名参数为0
+	Index int
+	// Reason 错误原因：unbound（未绑定）、invalid（参数值无法转换）、list（列表参数用于IN列表以外）
+	// 或 extra（按位置传入的参数多于子句中的 ?，Index 为第一个多余的参数）
+	Reason string
+	// Err 参数值无法转换时的原始错误
+	Err error
+	ctx errorContext
+}
+
+// newParamError 创建参数错误，name 为不含前导冒号的参数名，位置参数为 vN
+func newParamError(name, reason string, err error) *ParamError {
+	paramErr := &ParamError{Location: noLocation, Param: ":" + name, Reason: reason, Err: err}
+	if n, ok := positionalIndex(name); ok {
+		paramErr.Param, paramErr.Index = "?", n
+	}
+	return paramErr
+}
+
+// Code 返回错误码 PARAM_ERROR
+func (e *ParamError) Code() string {
+	return CodeParamError
+}
+
+// Error 返回本地化的错误信息
+func (e *ParamError) Error() string {
+	lang := e.ctx.lang
+	param := e.Param
+	if e.Index > 0 {
+		param = lang.text(msgPositionalParam, e.Index)
+	}
+	var message string
+	switch e.Reason {
+	case paramUnbound:
+		message = lang.text(msgParamUnbound, param)
+	case paramList:
+		message = lang.text(msgParamList, param)
+	case paramExtra:
+		message = lang.text(msgParamExtra, param)
+	default:
+		message = lang.text(msgParamInvalid, param, lang.cause(e.Err))
+	}
+	return lang.locate(message, e.Location)
+}
+
+// Unwrap 返回参数值无法转换时的原始错误
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ParamError) context() *errorContext {
+	return &e.ctx
+}
+
+func (e *ParamError) located(loc Location, ctx errorContext) locatedError {
+	copied := *e
+	copied.Location, copied.ctx = loc, ctx
+	return &copied
+}
+
+// 无效参数的种类，用于 InvalidArgumentError.Reason
+const (
+	reasonArity      = "arity"
+	reasonArgument   = "argument"
+	reasonRegexp     = "regexp"
+	reasonEscape     = "escape"
+	reasonOperands   = "operands"
+	reasonLiteral    = "literal"
+	reasonOutOfRange = "out of range"
+	reasonValuer     = "valuer"
+	reasonFunction   = "function"
+	reasonModel      = "model"
+)
+
+// InvalidArgumentError 函数参数、操作数、字面量或模型无效
+type InvalidArgumentError struct {
+	Location
+	// Reason 错误原因：arity（函数参数个数错误）、argument（自定义函数参数无法转换）、regexp（正则表达式无效或过长）、
+	// escape（ESCAPE不是单个字符）、operands（XOR操作数不足）、literal（无法解析的数值字面量）、
+	// out of range（整数超出范围）、valuer（driver.Valuer 无法返回值）、function（注册的函数名或签名无效）
+	// 或 model（模型为nil）
+	Reason string
+	// Name 出错的函数名、操作符、正则表达式、值或 driver.Valuer 的类型名
+	Name string
+	// Err 导致错误的原始错误，如正则表达式的编译错误，没有时为nil
+	Err  error
+	msg  messageID
+	args []interface{}
+	ctx  errorContext
+}
+
+// newInvalidArgument 创建无效参数错误，msg 和 args 为错误信息的模板和参数，args 中的error按错误的语言格式化
+func newInvalidArgument(reason, name string, err error, msg messageID, args ...interface{}) *InvalidArgumentError {
+	return &InvalidArgumentError{Location: noLocation, Reason: reason, Name: name, Err: err, msg: msg, args: args}
+}
+
+// newOutOfRange 创建整数超出范围的错误
+func newOutOfRange(value interface{}) *InvalidArgumentError {
+	name := fmt.Sprint(value)
+	return newInvalidArgument(reasonOutOfRange, name, nil, msgOutOfRange, name)
+}
+
+// Code 返回错误码 INVALID_ARGUMENT
+func (e *InvalidArgumentError) Code() string {
+	return CodeInvalidArgument
+}
+
+// Error 返回本地化的错误信息
+func (e *InvalidArgumentError) Error() string {
+	lang := e.ctx.lang
+	args := make([]interface{}, len(e.args))
+	for i, arg := range e.args {
+		if err, ok := arg.(error); ok {
+			arg = lang.cause(err)
+		}
+		args[i] = arg
+	}
+	return lang.locate(lang.text(e.msg, args...), e.Location)
+}
+
+// Unwrap 返回导致错误的原始错误
+func (e *InvalidArgumentError) Unwrap() error {
+	return e.Err
+}
+
+func (e *InvalidArgumentError) context() *errorContext {
+	return &e.ctx
+}
+
+func (e *InvalidArgumentError) located(loc Location, ctx errorContext) locatedError {
+	copied := *e
+	copied.Location, copied.ctx = loc, ctx
+	return &copied
+}
+
+// FunctionError 自定义函数返回了错误
+type FunctionError struct {
+	Location
+	// Function 函数名，大写
+	Function string
+	// Err 函数返回的错误
+	Err error
+	ctx errorContext
+}
+
+// Code 返回错误码 FUNCTION_ERROR
+func (e *FunctionError) Code() string {
+	return CodeFunctionError
+}
+
+// Error 返回本地化的错误信息
+func (e *FunctionError) Error() string {
+	lang := e.ctx.lang
+	return lang.locate(lang.text(msgFunctionFailed, e.Function, e.Err), e.Location)
+}
+
+// Unwrap 返回函数返回的错误
+func (e *FunctionError) Unwrap() error {
+	return e.Err
+}
+
+func (e *FunctionError) context() *errorContext {
+	return &e.ctx
+}
+
+func (e *FunctionError) located(loc Location, ctx errorContext) locatedError {
+	copied := *e
+	copied.Location, copied.ctx = loc, ctx
+	return &copied
+}
@@ -0,0 +1,395 @@
+package sqlevaluator
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+func TestStructuredErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		whereClause  string
+		wantCode     string
+		wantPos      int
+		wantFragment string
+		wantErr      bool
+	}{
+		{
+			name:         "字段未找到",
+			whereClause:  "age > 18 AND agee > 20",
+			wantCode:     CodeFieldNotFound,
+			wantPos:      13,
+			wantFragment: "agee",
+			wantErr:      true,
+		},
+		{
+			name:         "嵌套字段不是结构体",
+			whereClause:  "name.first = 'a'",
+			wantCode:     CodeFieldNotFound,
+			wantPos:      0,
+			wantFragment: "name.first",
+			wantErr:      true,
+		},
+		{
+			name:         "比较类型不匹配",
+			whereClause:  "is_active > 'abc'",
+			wantCode:     CodeTypeMismatch,
+			wantPos:      0,
+			wantFragment: "is_active > 'abc'",
+			wantErr:      true,
+		},
+		{
+			name:         "算术运算的操作数",
+			whereClause:  "age + 'x' > 1",
+			wantCode:     CodeTypeMismatch,
+			wantPos:      6,
+			wantFragment: "'x'",
+			wantErr:      true,
+		},
+		{
+			name:         "BETWEEN的边界",
+			whereClause:  "age BETWEEN 'b' AND 10",
+			wantCode:     CodeTypeMismatch,
+			wantPos:      12,
+			wantFragment: "'b'",
+			wantErr:      true,
+		},
+		{
+			name:         "不支持的函数",
+			whereClause:  "age > 1 AND unknown_func(name) = 1",
+			wantCode:     CodeUnsupportedExpr,
+			wantPos:      12,
+			wantFragment: "unknown_func(name)",
+			wantErr:      true,
+		},
+		{
+			name:         "语法错误",
+			whereClause:  "age > > 1",
+			wantCode:     CodeParseError,
+			wantPos:      6,
+			wantFragment: ">",
+			wantErr:      true,
+		},
+		{
+			name:         "子句不完整",
+			whereClause:  "age >",
+			wantCode:     CodeParseError,
+			wantPos:      5,
+			wantFragment: "",
+			wantErr:      true,
+		},
+		{
+			name:        "有效的子句",
+			whereClause: "age > 18 AND name LIKE '张%'",
+			wantErr:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(&UserWithNonPtr{Name: "张三", Age: 20})
+			_, err := evaluator.EvaluateWhere(tt.whereClause)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				return
+			}
+
+			var sqlErr SQLError
+			if !errors.As(err, &sqlErr) {
+				t.Fatalf("EvaluateWhere() error = %v (%T), want SQLError", err, err)
+			}
+			pos, fragment := sqlErr.Position()
+			if sqlErr.Code() != tt.wantCode || pos != tt.wantPos || fragment != tt.wantFragment {
+				t.Errorf("EvaluateWhere() error = %s %d %q, want %s %d %q",
+					sqlErr.Code(), pos, fragment, tt.wantCode, tt.wantPos, tt.wantFragment)
+			}
+		})
+	}
+}
+
+func TestStructuredErrorTypes(t *testing.T) {
+	model := &UserWithNonPtr{Name: "张三", Age: 20}
+
+	_, err := NewSQLEvaluator(model).EvaluateWhere("address.city = 'Beijing'")
+	var notFound *FieldNotFoundError
+	if !errors.As(err, &notFound) || notFound.Field != "address.city" {
+		t.Errorf("EvaluateWhere() error = %v, want *FieldNotFoundError for address.city", err)
+	}
+
+	_, err = NewSQLEvaluator(model).EvaluateWhere("name LIKE '张%' AND is_active < 'x'")
+	var mismatch *TypeMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Left != "bool" || mismatch.Right != "string" || mismatch.Operator != "<" {
+		t.Errorf("EvaluateWhere() error = %v, want *TypeMismatchError bool < string", err)
+	}
+
+	_, err = NewSQLEvaluator(model).EvaluateWhere("age > 1 AND unknown_func(age)")
+	var unsupported *UnsupportedExprError
+	if !errors.As(err, &unsupported) || unsupported.Construct != "function" || unsupported.Name != "unknown_func" {
+		t.Errorf("EvaluateWhere() error = %v, want *UnsupportedExprError for unknown_func", err)
+	}
+
+	_, err = Compile("age > > 1")
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Pos != 6 {
+		t.Errorf("Compile() error = %v, want *ParseError at 6", err)
+	}
+
+	_, err = NewSQLEvaluator(model).EvaluateWhere("age > ?")
+	var paramErr *ParamError
+	if !errors.As(err, &paramErr) || paramErr.Param != "?" || paramErr.Index != 1 || paramErr.Reason != "unbound" {
+		t.Errorf("EvaluateWhere() error = %v, want *ParamError for ? #1", err)
+	}
+
+	_, err = NewSQLEvaluator(model).EvaluateWhere("name REGEXP '('")
+	var invalid *InvalidArgumentError
+	if !errors.As(err, &invalid) || invalid.Reason != "regexp" || invalid.Name != "(" || invalid.Unwrap() == nil {
+		t.Errorf("EvaluateWhere() error = %v, want *InvalidArgumentError for regexp", err)
+	}
+}
+
+func TestStructuredErrorMessages(t *testing.T) {
+	evaluator := NewSQLEvaluator(&UserWithNonPtr{Name: "张三", Age: 20}, WithLanguage(English))
+	evaluator.RegisterFunction("fail", func(s string) (bool, error) {
+		return false, errors.New("boom")
+	})
+	evaluator.RegisterFunction("small", func(n int8) bool {
+		return n > 0
+	})
+
+	tests := []struct {
+		name        string
+		model       interface{}
+		whereClause string
+		args        []interface{}
+		named       map[string]interface{}
+		wantCode    string
+		want        string
+	}{
+		{
+			name:        "位置参数未绑定",
+			whereClause: "age > ?",
+			wantCode:    CodeParamError,
+			want:        "parameter ? #1 is not bound",
+		},
+		{
+			name:        "命名参数未绑定",
+			whereClause: "age > :min",
+			named:       map[string]interface{}{},
+			wantCode:    CodeParamError,
+			want:        "parameter :min is not bound",
+		},
+		{
+			name:        "列表参数用于比较",
+			whereClause: "age = ?",
+			args:        []interface{}{[]int{1, 2}},
+			wantCode:    CodeParamError,
+			want:        `at position 6 "?": list parameter ? #1 can only be used in an IN list`,
+		},
+		{
+			name:        "多余的位置参数",
+			whereClause: "age = ?",
+			args:        []interface{}{30, 40},
+			wantCode:    CodeParamError,
+			want:        "extra argument: the clause has no ? #2",
+		},
+		{
+			name:        "参数值无法转换",
+			whereClause: "age > ?",
+			args:        []interface{}{Broken{}},
+			wantCode:    CodeParamError,
+			want:        "invalid parameter ? #1: failed to get value of sqlevaluator.Broken: 连接已关闭",
+		},
+		{
+			name:        "函数参数个数",
+			whereClause: "LOWER(name, age) = 'a'",
+			wantCode:    CodeInvalidArgument,
+			want:        `at position 0 "LOWER(name, age)": function LOWER expects 1 arguments, got 2`,
+		},
+		{
+			name:        "无效的正则表达式",
+			whereClause: "name REGEXP '('",
+			wantCode:    CodeInvalidArgument,
+			want:        "at position 0 \"name REGEXP '('\": invalid regular expression \"(\": error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name:        "无效的ESCAPE",
+			whereClause: "name LIKE 'a' ESCAPE 'ab'",
+			wantCode:    CodeInvalidArgument,
+			want:        `at position 0 "name LIKE 'a' ESCAPE 'ab'": ESCAPE must be a single character: ab`,
+		},
+		{
+			name:        "整数运算溢出",
+			whereClause: "age * 9223372036854775807 > 1",
+			wantCode:    CodeInvalidArgument,
+			want:        `at position 0 "age * 9223372036854775807": integer out of range: 184467440737095516140`,
+		},
+		{
+			name:        "自定义函数参数超出范围",
+			whereClause: "small(age * 100)",
+			wantCode:    CodeInvalidArgument,
+			want:        `at position 0 "small(age * 100)": argument 1 of function SMALL has the wrong type: integer out of range: 2000`,
+		},
+		{
+			name:        "自定义函数返回错误",
+			whereClause: "fail(name)",
+			wantCode:    CodeFunctionError,
+			want:        `at position 0 "fail(name)": function FAIL failed: boom`,
+		},
+		{
+			name:        "无法解析的时间",
+			model:       &Event{},
+			whereClause: "created_at > 'yesterday'",
+			wantCode:    CodeTypeMismatch,
+			want:        `at position 0 "created_at > 'yesterday'": type mismatch: cannot convert string to time`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := *evaluator
+			if tt.model != nil {
+				e.model = tt.model
+			}
+			var err error
+			if tt.named != nil {
+				_, err = e.EvaluateWhereNamed(tt.whereClause, tt.named)
+			} else {
+				_, err = e.EvaluateWhere(tt.whereClause, tt.args...)
+			}
+
+			var sqlErr SQLError
+			if !errors.As(err, &sqlErr) {
+				t.Fatalf("EvaluateWhere() error = %v (%T), want SQLError", err, err)
+			}
+			if sqlErr.Code() != tt.wantCode || err.Error() != tt.want {
+				t.Errorf("EvaluateWhere() error = %s %v, want %s %v", sqlErr.Code(), err, tt.wantCode, tt.want)
+			}
+		})
+	}
+
+	errs := Validate("age > 1", nil, WithLanguage(English))
+	var invalid *InvalidArgumentError
+	if len(errs) != 1 || !errors.As(errs[0].Err, &invalid) || errs.Error() != "model must not be nil" {
+		t.Errorf("Validate() nil模型 = %v, want model must not be nil", errs)
+	}
+
+	// 改写后的XOR至少有两个操作数，直接构造只有一个操作数的语法树
+	single := &sqlparser.FuncExpr{
+		Name:  sqlparser.NewColIdent(xorFuncName),
+		Exprs: sqlparser.SelectExprs{&sqlparser.AliasedExpr{Expr: sqlparser.BoolVal(true)}},
+	}
+	_, err := evaluator.evaluateXor(single)
+	if !errors.As(err, &invalid) || invalid.Reason != "operands" ||
+		resolveError(err, nil, English).Error() != "XOR requires at least two operands" {
+		t.Errorf("evaluateXor() error = %v, want XOR requires at least two operands", err)
+	}
+
+	_, err = FilterCompiled([]map[string]interface{}{{"age": 1}, {"age": "x"}}, MustCompile("age + 1 > 0", WithLanguage(English)))
+	var elemErr *ElementError
+	if !errors.As(err, &elemErr) || !errors.As(err, new(*TypeMismatchError)) ||
+		err.Error() != `failed to evaluate element 1: at position 0 "age": type mismatch: cannot convert string to number` {
+		t.Errorf("FilterCompiled() error = %v, want localized *ElementError", err)
+	}
+}
+
+func TestErrorLanguage(t *testing.T) {
+	tests := []struct {
+		name        string
+		whereClause string
+		opts        []Option
+		want        string
+	}{
+		{
+			name:        "默认中文",
+			whereClause: "agee > 20",
+			want:        `位置0 "agee": 字段未找到: agee`,
+		},
+		{
+			name:        "英文",
+			whereClause: "agee > 20",
+			opts:        []Option{WithLanguage(English)},
+			want:        `at position 0 "agee": field not found: agee`,
+		},
+		{
+			name:        "英文的类型不匹配",
+			whereClause: "is_active > 'abc'",
+			opts:        []Option{WithLanguage(English)},
+			want:        `at position 0 "is_active > 'abc'": type mismatch: bool > string`,
+		},
+		{
+			name:        "中文的类型不匹配",
+			whereClause: "is_active > 'abc'",
+			opts:        []Option{WithLanguage(Chinese)},
+			want:        `位置0 "is_active > 'abc'": 类型不匹配: 布尔值 > 字符串`,
+		},
+		{
+			name:        "英文的语法错误",
+			whereClause: "age > > 1",
+			opts:        []Option{WithLanguage(English)},
+			want:        `at position 6 ">": failed to parse SQL: syntax error`,
+		},
+		{
+			name:        "英文的不支持的函数",
+			whereClause: "unknown_func(age) = 1",
+			opts:        []Option{WithLanguage(English)},
+			want:        `at position 0 "unknown_func(age)": unsupported function: unknown_func`,
+		},
+		{
+			name:        "不支持的语言使用中文",
+			whereClause: "agee > 20",
+			opts:        []Option{WithLanguage("fr")},
+			want:        `位置0 "agee": 字段未找到: agee`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(&UserWithNonPtr{}, tt.opts...)
+			_, err := evaluator.EvaluateWhere(tt.whereClause)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("EvaluateWhere() error = %v, want %v", err, tt.want)
+			}
+
+			errs := Validate(tt.whereClause, &UserWithNonPtr{}, tt.opts...)
+			if len(errs) != 1 || errs.Error() != tt.want {
+				t.Errorf("Validate() = %v, want %v", errs, tt.want)
+			}
+		})
+	}
+}
+
+// TestCachedErrorsNotShared 缓存的列解析错误在不同子句和语言之间互不影响
+func TestCachedErrorsNotShared(t *testing.T) {
+	compiled := []*CompiledWhere{
+		MustCompile("agee > 1"),
+		MustCompile("age > 1 AND agee > 1", WithLanguage(English)),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c := compiled[i%2]
+			_, err := c.Evaluate(&UserWithNonPtr{Age: 2})
+			var notFound *FieldNotFoundError
+			if !errors.As(err, &notFound) {
+				t.Errorf("Evaluate() error = %v, want *FieldNotFoundError", err)
+				return
+			}
+			if i%2 == 0 && (notFound.Pos != 0 || !strings.Contains(err.Error(), "字段未找到")) {
+				t.Errorf("Evaluate() error = %v, want 字段未找到 at 0", err)
+			}
+			if i%2 == 1 && (notFound.Pos != 12 || !strings.Contains(err.Error(), "field not found")) {
+				t.Errorf("Evaluate() error = %v, want field not found at 12", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -1288,3 +1288,691 @@ func BenchmarkSQLEvaluatorComplex(b *testing.B) {
 		}
 	}
 }
+
+// TestSQLEvaluatorMapValues 测试map类型的模型
+func TestSQLEvaluatorMapValues(t *testing.T) {
+	// 模拟由JSON解码得到的记录，数值均为float64
+	record := map[string]interface{}{
+		"id":        float64(1),
+		"name":      "张三",
+		"age":       float64(25),
+		"salary":    5000.50,
+		"is_active": true,
+		"nickname":  nil,
+		"address": map[string]interface{}{
+			"city": "Beijing",
+			"geo": map[string]interface{}{
+				"zip": float64(100000),
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		model       interface{}
+		whereClause string
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "map字符串比较",
+			model:       record,
+			whereClause: "name = '张三'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "map数值与整数比较",
+			model:       record,
+			whereClause: "age = 25 AND salary > 5000",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "map布尔值比较",
+			model:       record,
+			whereClause: "is_active = true",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "map指针模型",
+			model:       &record,
+			whereClause: "id = 1",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "map值为nil视为NULL",
+			model:       record,
+			whereClause: "nickname IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "map键不存在视为NULL",
+			model:       record,
+			whereClause: "email IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "map NULL值比较",
+			model:       record,
+			whereClause: "nickname = ''",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "map IN操作符",
+			model:       record,
+			whereClause: "age IN (20, 25, 30)",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "map BETWEEN操作符",
+			model:       record,
+			whereClause: "salary BETWEEN 5000 AND 6000",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "map LIKE操作符",
+			model:       record,
+			whereClause: "name LIKE '张%'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "嵌套map",
+			model:       record,
+			whereClause: "address.city = 'Beijing'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "多层嵌套map",
+			model:       record,
+			whereClause: "address.geo.zip = 100000",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "嵌套键不存在视为NULL",
+			model:       record,
+			whereClause: "address.street IS NULL AND profile.bio IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "访问非map字段的嵌套键",
+			model:       record,
+			whereClause: "name.first = '张'",
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name: "map[string]string",
+			model: map[string]string{
+				"name": "李四",
+				"age":  "30",
+			},
+			whereClause: "name = '李四' AND age > 25",
+			want:        true,
+			wantErr:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(tt.model)
+			got, err := evaluator.EvaluateWhere(tt.whereClause)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Geo 示例地理位置模型
+type Geo struct {
+	Zip string `json:"zip"`
+}
+
+// Address 示例地址模型
+type Address struct {
+	City string `json:"city"`
+	Geo  *Geo   `json:"geo"`
+}
+
+// AuditInfo 示例审计信息（用于匿名嵌入）
+type AuditInfo struct {
+	CreatedBy string `json:"created_by"`
+	Version   int
+}
+
+// Customer 示例客户模型（嵌套结构体与匿名嵌入结构体）
+type Customer struct {
+	*AuditInfo
+	ID       int                    `json:"id"`
+	Name     string                 `json:"name"`
+	Address  Address                `json:"address"`
+	Billing  *Address               `json:"billing_address"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// TestSQLEvaluatorNestedFields 测试嵌套结构体与匿名嵌入结构体字段
+func TestSQLEvaluatorNestedFields(t *testing.T) {
+	customer := &Customer{
+		AuditInfo: &AuditInfo{CreatedBy: "admin", Version: 2},
+		ID:        1,
+		Name:      "张三",
+		Address: Address{
+			City: "Beijing",
+			Geo:  &Geo{Zip: "100000"},
+		},
+		Billing: nil,
+		Metadata: map[string]interface{}{
+			"level": "gold",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		model       interface{}
+		whereClause string
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "嵌套结构体字段",
+			model:       customer,
+			whereClause: "address.city = 'Beijing'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "多级嵌套指针字段",
+			model:       customer,
+			whereClause: "address.geo.zip = '100000'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "嵌套字段名不区分大小写",
+			model:       customer,
+			whereClause: "Address.City LIKE 'Bei%'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "按json标签访问nil指针结构体",
+			model:       customer,
+			whereClause: "billing_address.city IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "nil指针结构体的字段比较",
+			model:       customer,
+			whereClause: "billing_address.city = 'Beijing'",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "匿名嵌入结构体的json标签",
+			model:       customer,
+			whereClause: "created_by = 'admin'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "匿名嵌入结构体的字段名",
+			model:       customer,
+			whereClause: "version >= 2",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "nil匿名嵌入结构体视为NULL",
+			model:       &Customer{ID: 2, Name: "李四"},
+			whereClause: "created_by IS NULL AND version IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "结构体中的嵌套map",
+			model:       customer,
+			whereClause: "metadata.level = 'gold'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "超过三级的路径使用反引号",
+			model:       map[string]interface{}{"customer": customer},
+			whereClause: "`customer.address.geo.zip` = '100000'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "表名限定的嵌套字段",
+			model:       customer,
+			whereClause: "customers.address.city = 'Beijing'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "嵌套字段不存在",
+			model:       customer,
+			whereClause: "address.street = 'x'",
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "表名限定的字段不存在",
+			model:       customer,
+			whereClause: "customers.agee = 1",
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "访问非结构体字段的嵌套字段",
+			model:       customer,
+			whereClause: "name.first = '张'",
+			want:        false,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(tt.model)
+			got, err := evaluator.EvaluateWhere(tt.whereClause)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSQLEvaluatorQualifiedColumns 测试表名限定的列名，第一级不是字段时按去掉表名的列名解析
+func TestSQLEvaluatorQualifiedColumns(t *testing.T) {
+	tests := []struct {
+		name        string
+		model       interface{}
+		whereClause string
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "表名限定的指针字段",
+			model:       &User{Age: intPtr(30)},
+			whereClause: "users.age > 20",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "库名和表名限定的字段",
+			model:       &UserWithNonPtr{Name: "张三", Age: 30},
+			whereClause: "db.users.name = '张三' AND users.age BETWEEN 20 AND 40",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "FieldGetter模型",
+			model:       &Profile{id: 7},
+			whereClause: "profiles.id = 7",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "去掉表名后字段仍不存在",
+			model:       &User{Age: intPtr(30)},
+			whereClause: "users.agee > 20",
+			want:        false,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(tt.model)
+			got, err := evaluator.EvaluateWhere(tt.whereClause)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+
+			if errs := Validate(tt.whereClause, tt.model); (errs != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", errs, tt.wantErr)
+			}
+		})
+	}
+
+	// 列描述与反射使用相同的规则
+	if errs := Validate("users.age > 20", SchemaOf((*User)(nil))); errs != nil {
+		t.Errorf("Validate() = %v, want nil", errs)
+	}
+}
+
+// TestSQLEvaluatorBooleanLogic 测试NOT、XOR、IS TRUE/IS FALSE和布尔列条件
+func TestSQLEvaluatorBooleanLogic(t *testing.T) {
+	user := &User{
+		ID:       intPtr(1),
+		Name:     strPtr("张三"),
+		Age:      intPtr(25),
+		Salary:   float64Ptr(5000.50),
+		IsActive: boolPtr(true),
+	}
+	inactive := &UserWithNonPtr{
+		ID:       2,
+		Name:     "李四",
+		Age:      17,
+		Salary:   0,
+		IsActive: false,
+	}
+	nullUser := &User{
+		ID: intPtr(3),
+	}
+
+	tests := []struct {
+		name        string
+		model       interface{}
+		whereClause string
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "NOT条件",
+			model:       user,
+			whereClause: "NOT (age > 20 AND name = '李四')",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "感叹号取反",
+			model:       user,
+			whereClause: "!(age < 20)",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "NOT布尔列",
+			model:       inactive,
+			whereClause: "NOT is_active",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "XOR一真一假",
+			model:       user,
+			whereClause: "age > 20 XOR name = '李四'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "XOR两真",
+			model:       user,
+			whereClause: "age > 20 XOR name = '张三'",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "XOR优先级低于AND",
+			model:       user,
+			whereClause: "age > 20 AND name = '张三' XOR salary > 10000",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "XOR优先级高于OR",
+			model:       user,
+			whereClause: "age > 20 OR age > 20 XOR is_active",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "XOR后的--注释",
+			model:       user,
+			whereClause: "age > 20 XOR name = '李四' -- 备注",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "XOR后的#注释",
+			model:       user,
+			whereClause: "age > 20 XOR name = '张三' # 备注",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "布尔列作为条件",
+			model:       user,
+			whereClause: "is_active AND age > 18",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "非指针布尔列作为条件",
+			model:       inactive,
+			whereClause: "is_active OR age > 18",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "数值列作为条件",
+			model:       inactive,
+			whereClause: "age AND NOT salary",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "NULL列作为条件",
+			model:       nullUser,
+			whereClause: "is_active",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "布尔字面量作为条件",
+			model:       user,
+			whereClause: "true AND NOT false",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "IS TRUE",
+			model:       user,
+			whereClause: "is_active IS TRUE AND (age > 20) IS TRUE",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "IS FALSE",
+			model:       inactive,
+			whereClause: "is_active IS FALSE AND (age > 20) IS FALSE",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "NULL IS NOT TRUE",
+			model:       nullUser,
+			whereClause: "is_active IS NOT TRUE AND is_active IS NOT FALSE",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "NULL既不是TRUE也不是FALSE",
+			model:       nullUser,
+			whereClause: "is_active IS TRUE OR is_active IS FALSE",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "IS NOT FALSE",
+			model:       user,
+			whereClause: "is_active IS NOT FALSE",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "字符串列无法作为条件",
+			model:       user,
+			whereClause: "name",
+			want:        false,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(tt.model)
+			got, err := evaluator.EvaluateWhere(tt.whereClause)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Quota 示例配额模型（用于列与列比较）
+type Quota struct {
+	UsedQuota  *int      `json:"used_quota"`
+	TotalQuota int       `json:"total_quota"`
+	SoftLimit  float64   `json:"soft_limit"`
+	MinQuota   int       `json:"min_quota"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TestSQLEvaluatorColumnComparison 测试列与列之间的比较
+func TestSQLEvaluatorColumnComparison(t *testing.T) {
+	created := time.Date(2024, 3, 21, 10, 0, 0, 0, time.UTC)
+	quota := &Quota{
+		UsedQuota:  intPtr(80),
+		TotalQuota: 100,
+		SoftLimit:  90.5,
+		MinQuota:   10,
+		CreatedAt:  created,
+		UpdatedAt:  created.Add(time.Hour),
+	}
+
+	tests := []struct {
+		name        string
+		model       interface{}
+		whereClause string
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "下划线命名的列比较",
+			model:       quota,
+			whereClause: "used_quota <= total_quota",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "时间列比较",
+			model:       quota,
+			whereClause: "updated_at > created_at",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "整数列与浮点数列比较",
+			model:       quota,
+			whereClause: "used_quota < soft_limit AND total_quota > soft_limit",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "列在右侧与字面量比较",
+			model:       quota,
+			whereClause: "50 < used_quota",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "列与算术表达式比较",
+			model:       quota,
+			whereClause: "used_quota + min_quota < total_quota",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "IN列表中的列",
+			model:       quota,
+			whereClause: "total_quota IN (min_quota, used_quota + 20)",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "BETWEEN边界中的列",
+			model:       quota,
+			whereClause: "used_quota BETWEEN min_quota AND total_quota",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "NULL列参与比较",
+			model:       &Quota{TotalQuota: 100},
+			whereClause: "used_quota <= total_quota OR total_quota >= used_quota",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "右侧列不存在",
+			model:       quota,
+			whereClause: "used_quota <= max_quota",
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "嵌套字段与顶层字段比较",
+			model:       map[string]interface{}{"max_count": float64(5), "usage": map[string]interface{}{"count": float64(3)}},
+			whereClause: "usage.count < max_count",
+			want:        true,
+			wantErr:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(tt.model)
+			got, err := evaluator.EvaluateWhere(tt.whereClause)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package sqlevaluator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// Explanation WHERE子句的评估过程
+type Explanation struct {
+	// Result 评估结果，与 EvaluateWhere 的返回值相同
+	Result bool `json:"result"`
+	// Root 整个子句的评估过程，子句为空时为nil
+	Root *ExplainNode `json:"root,omitempty"`
+}
+
+// ExplainNode 一个条件的评估过程，子节点与语法树中的子条件对应
+//
+// 括号不单独作为节点。作为值使用的条件（如 IF(age >= 18, ...) 中的 age >= 18）也记录为子节点；
+// AND、OR短路求值时未评估的右侧没有对应的节点。
+type ExplainNode struct {
+	// Fragment 条件在子句中的原始文本，无法定位时为语法树格式化后的文本
+	Fragment string `json:"fragment"`
+	// Pos 条件在子句中的字节偏移，无法定位时为-1
+	Pos int `json:"pos"`
+	// Operator 比较、IN、BETWEEN和IS条件的操作符，如 >、in、between、is null
+	Operator string `json:"operator,omitempty"`
+	// Field 条件中的列按 getFieldName 规则解析得到的Go字段路径，如 Address.City，两侧都是列时为左侧的列
+	Field string `json:"field,omitempty"`
+	// Left、Right 参与比较的值，比较前已按评估规则转换类型；IN条件的 Right 为值列表，BETWEEN条件的 Right 为两个边界
+	Left  interface{} `json:"-"`
+	Right interface{} `json:"-"`
+	// Result 条件的结果：TRUE、FALSE 或 UNKNOWN
+	Result string `json:"result"`
+	// Children 子条件的评估过程，按在子句中的顺序排列
+	Children []*ExplainNode `json:"children,omitempty"`
+
+	// hasLeft、hasRight 是否记录了对应的值，用于区分未记录和值为NULL
+	hasLeft, hasRight bool
+}
+
+// HasLeft 判断是否记录了左侧的值
+func (n *ExplainNode) HasLeft() bool {
+	return n.hasLeft
+}
+
+// HasRight 判断是否记录了右侧的值
+func (n *ExplainNode) HasRight() bool {
+	return n.hasRight
+}
+
+// EvaluateWhereExplain 评估WHERE子句并返回评估过程，用于排查条件为什么匹配或不匹配
+//
+// args 的含义与 EvaluateWhere 相同。记录评估过程有额外开销，批量评估时请使用 EvaluateWhere 或 Compile。
+func (e *SQLEvaluator) EvaluateWhereExplain(whereClause string, args ...interface{}) (*Explanation, error) {
+	params, err := positionalParams(args)
+	if err != nil {
+		return nil, resolveError(err, nil, e.options.language)
+	}
+	compiled, err := compile(whereClause, e.options, e.functions)
+	if err != nil {
+		return nil, err
+	}
+	bound := *e
+	bound.params, bound.args = params, len(args)
+	bound.trace = &tracer{locator: compiled.nodeLocator()}
+	result, err := bound.evaluateCompiled(compiled)
+	if err != nil {
+		return nil, err
+	}
+	return &Explanation{Result: result, Root: bound.trace.root}, nil
+}
+
+// tracer 评估过程中记录条件的结果
+type tracer struct {
+	locator *clauseLocator
+	root    *ExplainNode
+	// stack 正在评估的条件，栈顶为当前条件
+	stack []*ExplainNode
+}
+
+// enter 开始评估条件，返回新建的节点，括号返回nil
+func (t *tracer) enter(e *SQLEvaluator, expr sqlparser.Expr) *ExplainNode {
+	if _, ok := expr.(*sqlparser.ParenExpr); ok {
+		return nil
+	}
+
+	node := &ExplainNode{}
+	node.Pos, node.Fragment = t.locator.position(expr)
+	if node.Pos < 0 {
+		node.Fragment = sqlparser.String(expr)
+	}
+
+	var columns []sqlparser.Expr
+	switch n := expr.(type) {
+	case *sqlparser.ComparisonExpr:
+		node.Operator = n.Operator
+		columns = []sqlparser.Expr{n.Left, n.Right}
+	case *sqlparser.RangeCond:
+		node.Operator = n.Operator
+		columns = []sqlparser.Expr{n.Left, n.From, n.To}
+	case *sqlparser.IsExpr:
+		node.Operator = n.Operator
+		columns = []sqlparser.Expr{n.Expr}
+	case *sqlparser.ColName:
+		columns = []sqlparser.Expr{n}
+	}
+	for _, column := range columns {
+		if col, ok := column.(*sqlparser.ColName); ok {
+			// 字段无法解析时评估会返回错误，这里不需要处理
+			node.Field, _ = e.resolveFieldPath(columnPath(col))
+			break
+		}
+	}
+
+	if len(t.stack) == 0 {
+		t.root = node
+	} else {
+		parent := t.stack[len(t.stack)-1]
+		parent.Children = append(parent.Children, node)
+	}
+	t.stack = append(t.stack, node)
+	return node
+}
+
+// leave 结束条件的评估并记录结果
+func (t *tracer) leave(node *ExplainNode, result truth) {
+	node.Result = result.String()
+	t.stack = t.stack[:len(t.stack)-1]
+}
+
+// current 返回正在评估的条件
+func (t *tracer) current() *ExplainNode {
+	if len(t.stack) == 0 {
+		return nil
+	}
+	return t.stack[len(t.stack)-1]
+}
+
+// traceLeft 记录当前条件左侧的值，未记录评估过程时不做任何事
+func (e *SQLEvaluator) traceLeft(value interface{}) {
+	if e.trace == nil {
+		return
+	}
+	if node := e.trace.current(); node != nil {
+		node.Left, node.hasLeft = value, true
+	}
+}
+
+// traceRight 记录当前条件右侧的值，未记录评估过程时不做任何事
+func (e *SQLEvaluator) traceRight(value interface{}) {
+	if e.trace == nil {
+		return
+	}
+	if node := e.trace.current(); node != nil {
+		node.Right, node.hasRight = value, true
+	}
+}
+
+// String 以缩进的文本形式返回评估过程，每行为一个条件的结果、原始文本、Go字段和参与比较的值
+func (x *Explanation) String() string {
+	var sb strings.Builder
+	if x.Root == nil {
+		fmt.Fprintf(&sb, "%s (空条件)\n", truthOf(x.Result))
+		return sb.String()
+	}
+	x.Root.write(&sb, 0)
+	return sb.String()
+}
+
+// write 写入节点及其子节点的文本，depth 为缩进层级
+func (n *ExplainNode) write(sb *strings.Builder, depth int) {
+	fmt.Fprintf(sb, "%s%-7s %s", strings.Repeat("  ", depth), n.Result, n.Fragment)
+	if n.Field != "" {
+		fmt.Fprintf(sb, "  field=%s", n.Field)
+	}
+	if n.hasLeft {
+		fmt.Fprintf(sb, "  left=%s", formatExplainValue(n.Left))
+	}
+	if n.hasRight {
+		fmt.Fprintf(sb, "  right=%s", formatExplainValue(n.Right))
+	}
+	sb.WriteByte('\n')
+	for _, child := range n.Children {
+		child.write(sb, depth+1)
+	}
+}
+
+// formatExplainValue 按SQL字面量的形式格式化值，NULL显示为 NULL，列表显示为 (a, b)
+func formatExplainValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case *big.Rat:
+		return ratString(v)
+	case time.Time:
+		return "'" + v.Format(time.RFC3339Nano) + "'"
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatExplainValue(item)
+		}
+		return "(" + strings.Join(items, ", ") + ")"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// JSON 以带缩进的JSON格式返回评估过程
+func (x *Explanation) JSON() ([]byte, error) {
+	return json.MarshalIndent(x, "", "  ")
+}
+
+// explainNodeJSON ExplainNode 的JSON格式，记录了的值即使为NULL也会输出
+type explainNodeJSON struct {
+	*explainNodeFields
+	Left  *explainValue `json:"left,omitempty"`
+	Right *explainValue `json:"right,omitempty"`
+}
+
+// explainNodeFields 避免 MarshalJSON 递归调用
+type explainNodeFields ExplainNode
+
+// MarshalJSON 输出节点的JSON，left 和 right 只在记录了值时输出
+func (n *ExplainNode) MarshalJSON() ([]byte, error) {
+	out := explainNodeJSON{explainNodeFields: (*explainNodeFields)(n)}
+	if n.hasLeft {
+		out.Left = &explainValue{n.Left}
+	}
+	if n.hasRight {
+		out.Right = &explainValue{n.Right}
+	}
+	return json.Marshal(out)
+}
+
+// explainValue JSON中参与比较的值，十进制数值输出为JSON数值
+type explainValue struct {
+	value interface{}
+}
+
+// MarshalJSON 输出值的JSON
+func (v *explainValue) MarshalJSON() ([]byte, error) {
+	switch value := v.value.(type) {
+	case *big.Rat:
+		return []byte(ratString(value)), nil
+	case []interface{}:
+		items := make([]*explainValue, len(value))
+		for i, item := range value {
+			items[i] = &explainValue{item}
+		}
+		return json.Marshal(items)
+	default:
+		return json.Marshal(value)
+	}
+}
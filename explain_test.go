@@ -0,0 +1,151 @@
+package sqlevaluator
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestEvaluateWhereExplain(t *testing.T) {
+	customer := &Customer{
+		AuditInfo: &AuditInfo{Version: 2},
+		Name:      "张三",
+		Address:   Address{City: "Shanghai"},
+	}
+
+	tests := []struct {
+		name        string
+		whereClause string
+		args        []interface{}
+		opts        []Option
+		want        bool
+		wantText    string
+		wantErr     bool
+	}{
+		{
+			name:        "逻辑组合",
+			whereClause: "(address.city = 'Beijing' OR address.city IN ('Shanghai', ?)) AND NOT version BETWEEN 1 AND 3",
+			args:        []interface{}{"Hangzhou"},
+			want:        false,
+			wantText: "FALSE   (address.city = 'Beijing' OR address.city IN ('Shanghai', ?)) AND NOT version BETWEEN 1 AND 3\n" +
+				"  TRUE    address.city = 'Beijing' OR address.city IN ('Shanghai', ?)\n" +
+				"    FALSE   address.city = 'Beijing'  field=Address.City  left='Shanghai'  right='Beijing'\n" +
+				"    TRUE    address.city IN ('Shanghai', ?)  field=Address.City  left='Shanghai'  right=('Shanghai', 'Hangzhou')\n" +
+				"  FALSE   NOT version BETWEEN 1 AND 3\n" +
+				"    TRUE    version BETWEEN 1 AND 3  field=Version  left=2  right=(1, 3)\n",
+			wantErr: false,
+		},
+		{
+			name:        "比较前转换类型",
+			whereClause: "version >= '2' AND name LIKE '张%'",
+			want:        true,
+			wantText: "TRUE    version >= '2' AND name LIKE '张%'\n" +
+				"  TRUE    version >= '2'  field=Version  left=2  right=2\n" +
+				"  TRUE    name LIKE '张%'  field=Name  left='张三'  right='张%'\n",
+			wantErr: false,
+		},
+		{
+			name:        "三值逻辑的UNKNOWN",
+			whereClause: "billing_address.city = 'Beijing' OR metadata.level IS NULL",
+			opts:        []Option{WithThreeValuedLogic()},
+			want:        true,
+			wantText: "TRUE    billing_address.city = 'Beijing' OR metadata.level IS NULL\n" +
+				"  UNKNOWN billing_address.city = 'Beijing'  field=Billing.City  left=NULL\n" +
+				"  TRUE    metadata.level IS NULL  field=Metadata.level  left=NULL\n",
+			wantErr: false,
+		},
+		{
+			name:        "列作为条件和作为值的条件",
+			whereClause: "IF(version > 1, 'new', 'old') = 'new'",
+			want:        true,
+			wantText: "TRUE    IF(version > 1, 'new', 'old') = 'new'  left='new'  right='new'\n" +
+				"  TRUE    version > 1  field=Version  left=2  right=1\n",
+			wantErr: false,
+		},
+		{
+			name:        "空条件",
+			whereClause: "",
+			wantErr:     true,
+		},
+		{
+			name:        "评估错误",
+			whereClause: "version > 1 AND agee > 1",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(customer, tt.opts...)
+			explanation, err := evaluator.EvaluateWhereExplain(tt.whereClause, tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EvaluateWhereExplain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if explanation.Result != tt.want {
+				t.Errorf("EvaluateWhereExplain() result = %v, want %v", explanation.Result, tt.want)
+			}
+			if got := explanation.String(); got != tt.wantText {
+				t.Errorf("String() =\n%s\nwant\n%s", got, tt.wantText)
+			}
+
+			// 结果与 EvaluateWhere 一致
+			got, err := evaluator.EvaluateWhere(tt.whereClause, tt.args...)
+			if err != nil || got != explanation.Result {
+				t.Errorf("EvaluateWhere() = %v, %v, want %v", got, err, explanation.Result)
+			}
+		})
+	}
+}
+
+func TestExplanationJSON(t *testing.T) {
+	evaluator := NewSQLEvaluator(map[string]interface{}{"score": nil, "tags": "a"})
+	explanation, err := evaluator.EvaluateWhereExplain("score > 0.5 OR tags = 'a'")
+	if err != nil {
+		t.Fatalf("EvaluateWhereExplain() error = %v", err)
+	}
+
+	data, err := explanation.JSON()
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	want := map[string]interface{}{
+		"result": true,
+		"root": map[string]interface{}{
+			"fragment": "score > 0.5 OR tags = 'a'",
+			"pos":      float64(0),
+			"result":   "TRUE",
+			"children": []interface{}{
+				map[string]interface{}{
+					"fragment": "score > 0.5",
+					"pos":      float64(0),
+					"operator": ">",
+					"field":    "score",
+					"result":   "FALSE",
+					// 记录了的NULL值也会输出
+					"left": nil,
+				},
+				map[string]interface{}{
+					"fragment": "tags = 'a'",
+					"pos":      float64(15),
+					"operator": "=",
+					"field":    "tags",
+					"result":   "TRUE",
+					"left":     "a",
+					"right":    "a",
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("JSON() = %s", data)
+	}
+}
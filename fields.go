@@ -0,0 +1,209 @@
+package sqlevaluator
+
+import (
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// FieldRef 子句引用的一个列
+type FieldRef struct {
+	// Column SQL中的列名，限定列名以点连接，如 address.city；解析到同一字段的不同写法只记录第一次出现的写法，
+	// 没有模型时按不区分大小写的列名判断
+	Column string `json:"column"`
+	// Field 按 getFieldName 规则解析得到的Go字段路径，如 Address.City；map中的键原样保留，
+	// 模型实现了 FieldGetter 时为 GetField 接受的列名，没有模型时为空
+	Field string `json:"field,omitempty"`
+	// Operators 列参与的比较操作符，如 =、>、in、between、like、is null，按首次出现的顺序去重；
+	// 列在操作符右侧时 >、< 等操作符按列在左侧的方向记录，如 1 < age 记录为 >
+	Operators []string `json:"operators,omitempty"`
+	// LiteralTypes 与列比较的字面量类型：number、string、bool、null 或 param（参数），按首次出现的顺序去重
+	LiteralTypes []string `json:"literal_types,omitempty"`
+}
+
+// typeParam 参数占位符的字面量类型
+const typeParam = "param"
+
+// Fields 返回子句引用的全部列，按在子句中首次出现的顺序排列
+//
+// 列包括比较的任意一侧、函数和算术表达式的参数以及单独作为条件的列，Go字段按评估器的模型解析，
+// 列无法解析时返回 *FieldNotFoundError。可以用于只从数据库加载需要的列、构造缓存键，
+// 或在某个字段变化时判断需要重新评估哪些条件。
+func (e *SQLEvaluator) Fields(whereClause string) ([]FieldRef, error) {
+	compiled, err := compile(whereClause, e.options, e.functions)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.Fields(e.model)
+}
+
+// Fields 返回编译结果引用的全部列，Go字段按 model 解析，model 为nil时不解析Go字段，规则见 SQLEvaluator.Fields
+func (c *CompiledWhere) Fields(model interface{}) ([]FieldRef, error) {
+	if c.expr == nil {
+		return nil, nil
+	}
+
+	collector := &fieldCollector{byColumn: make(map[string]int), byKey: make(map[string]int)}
+	switch m := model.(type) {
+	case nil:
+	case FieldGetter:
+		collector.resolve = func(column string) (string, error) {
+			_, name, ok := getFieldQualified(m, column)
+			if !ok {
+				return "", newFieldNotFound(column)
+			}
+			return name, nil
+		}
+	default:
+		e := &SQLEvaluator{model: model, options: c.options}
+		collector.resolve = e.resolveFieldPath
+	}
+
+	collector.collect(c.expr)
+	if collector.err != nil {
+		return nil, c.resolveError(collector.err)
+	}
+	return collector.refs, nil
+}
+
+// fieldCollector 收集子句引用的列及其参与的比较
+type fieldCollector struct {
+	refs []FieldRef
+	// resolve 返回列的Go字段路径，为nil时不解析
+	resolve func(column string) (string, error)
+	// byColumn 列在 refs 中的下标，键为列在子句中的写法
+	byColumn map[string]int
+	// byKey 列在 refs 中的下标，键为解析得到的Go字段路径，没有模型时为小写的列名
+	byKey map[string]int
+	// err 第一个无法解析的列的错误
+	err error
+}
+
+// collect 按源码顺序收集列，并记录比较、IN、BETWEEN和IS条件中与列相关的操作符和字面量类型
+func (c *fieldCollector) collect(expr sqlparser.Expr) {
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch n := node.(type) {
+		case *sqlparser.ColName:
+			c.ref(n)
+		case *sqlparser.ComparisonExpr:
+			c.use(n.Left, n.Operator, n.Right)
+			c.use(n.Right, reverseOperator(n.Operator), n.Left)
+		case *sqlparser.RangeCond:
+			c.use(n.Left, n.Operator, n.From, n.To)
+		case *sqlparser.IsExpr:
+			c.use(n.Expr, n.Operator)
+		}
+		return true, nil
+	}, expr)
+}
+
+// ref 返回列对应的记录，第一次出现时新建；列无法解析时记录错误并返回不会被使用的记录
+func (c *fieldCollector) ref(col *sqlparser.ColName) *FieldRef {
+	column := columnPath(col)
+	if i, ok := c.byColumn[column]; ok {
+		return &c.refs[i]
+	}
+
+	key, field := strings.ToLower(column), ""
+	if c.resolve != nil {
+		var err error
+		if field, err = c.resolve(column); err != nil {
+			if c.err == nil {
+				c.err = withNode(err, col)
+			}
+			return &FieldRef{}
+		}
+		key = field
+	}
+
+	i, ok := c.byKey[key]
+	if !ok {
+		i = len(c.refs)
+		c.byKey[key] = i
+		c.refs = append(c.refs, FieldRef{Column: column, Field: field})
+	}
+	c.byColumn[column] = i
+	return &c.refs[i]
+}
+
+// use 为操作数中的每个列记录操作符和另一侧的字面量类型
+//
+// 操作数中嵌套的条件（如 IF(age > 1, ...) 中的 age > 1）由该条件自身记录。
+func (c *fieldCollector) use(operand sqlparser.Expr, operator string, others ...sqlparser.Expr) {
+	var literals []string
+	for _, other := range others {
+		literals = appendLiteralTypes(literals, other)
+	}
+
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch n := node.(type) {
+		case *sqlparser.ColName:
+			ref := c.ref(n)
+			ref.Operators = appendUnique(ref.Operators, operator)
+			for _, literal := range literals {
+				ref.LiteralTypes = appendUnique(ref.LiteralTypes, literal)
+			}
+		case *sqlparser.ComparisonExpr, *sqlparser.RangeCond, *sqlparser.IsExpr,
+			*sqlparser.AndExpr, *sqlparser.OrExpr, *sqlparser.NotExpr:
+			return false, nil
+		}
+		return true, nil
+	}, operand)
+}
+
+// appendLiteralTypes 将表达式中直接出现的字面量类型追加到 types，值列表中的每一项都计入
+func appendLiteralTypes(types []string, expr sqlparser.Expr) []string {
+	switch n := expr.(type) {
+	case *sqlparser.SQLVal:
+		switch n.Type {
+		case sqlparser.StrVal:
+			return append(types, typeString)
+		case sqlparser.IntVal, sqlparser.FloatVal, sqlparser.HexNum, sqlparser.HexVal, sqlparser.BitVal:
+			return append(types, typeNumber)
+		case sqlparser.ValArg:
+			return append(types, typeParam)
+		}
+	case sqlparser.BoolVal:
+		return append(types, typeBool)
+	case *sqlparser.NullVal:
+		return append(types, typeNull)
+	case sqlparser.ListArg:
+		return append(types, typeParam)
+	case *sqlparser.UnaryExpr:
+		// 负数字面量，如 -1.5
+		return appendLiteralTypes(types, n.Expr)
+	case *sqlparser.ParenExpr:
+		return appendLiteralTypes(types, n.Expr)
+	case sqlparser.ValTuple:
+		for _, item := range n {
+			types = appendLiteralTypes(types, item)
+		}
+	}
+	return types
+}
+
+// reverseOperator 返回交换比较两侧后等价的操作符，IN、LIKE等不对称的操作符原样返回
+func reverseOperator(operator string) string {
+	switch operator {
+	case sqlparser.LessThanStr:
+		return sqlparser.GreaterThanStr
+	case sqlparser.GreaterThanStr:
+		return sqlparser.LessThanStr
+	case sqlparser.LessEqualStr:
+		return sqlparser.GreaterEqualStr
+	case sqlparser.GreaterEqualStr:
+		return sqlparser.LessEqualStr
+	default:
+		return operator
+	}
+}
+
+// appendUnique 追加不在切片中的字符串
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,147 @@
+package sqlevaluator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSQLEvaluatorFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		model       interface{}
+		whereClause string
+		want        []FieldRef
+		wantErr     bool
+	}{
+		{
+			name:        "比较和字面量类型",
+			model:       &UserWithNonPtr{},
+			whereClause: "age > 18 AND name IN ('张三', ?) AND is_active = true AND salary BETWEEN -1 AND :max",
+			want: []FieldRef{
+				{Column: "age", Field: "Age", Operators: []string{">"}, LiteralTypes: []string{"number"}},
+				{Column: "name", Field: "Name", Operators: []string{"in"}, LiteralTypes: []string{"string", "param"}},
+				{Column: "is_active", Field: "IsActive", Operators: []string{"="}, LiteralTypes: []string{"bool"}},
+				{Column: "salary", Field: "Salary", Operators: []string{"between"}, LiteralTypes: []string{"number", "param"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "同一列多次出现",
+			model:       &UserWithNonPtr{},
+			whereClause: "AGE >= 18 AND (age < 60 OR age IS NULL) AND name LIKE '张%' AND name != ''",
+			want: []FieldRef{
+				{Column: "AGE", Field: "Age", Operators: []string{">=", "<", "is null"}, LiteralTypes: []string{"number"}},
+				{Column: "name", Field: "Name", Operators: []string{"like", "!="}, LiteralTypes: []string{"string"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "列在右侧和列之间的比较",
+			model:       &Quota{},
+			whereClause: "10 < used_quota AND updated_at > created_at",
+			want: []FieldRef{
+				{Column: "used_quota", Field: "UsedQuota", Operators: []string{">"}, LiteralTypes: []string{"number"}},
+				{Column: "updated_at", Field: "UpdatedAt", Operators: []string{">"}},
+				{Column: "created_at", Field: "CreatedAt", Operators: []string{"<"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "函数、算术表达式和单独作为条件的列",
+			model:       &UserWithNonPtr{},
+			whereClause: "LOWER(name) = 'a' AND salary * 12 > 1000 AND is_active AND IF(age > 18, 1, 0) = 1",
+			want: []FieldRef{
+				{Column: "name", Field: "Name", Operators: []string{"="}, LiteralTypes: []string{"string"}},
+				{Column: "salary", Field: "Salary", Operators: []string{">"}, LiteralTypes: []string{"number"}},
+				{Column: "is_active", Field: "IsActive"},
+				{Column: "age", Field: "Age", Operators: []string{">"}, LiteralTypes: []string{"number"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "嵌套字段和map",
+			model:       &Customer{},
+			whereClause: "address.city = 'Beijing' AND billing_address.geo.zip IS NOT NULL AND metadata.source = NULL AND created_by = 'admin'",
+			want: []FieldRef{
+				{Column: "address.city", Field: "Address.City", Operators: []string{"="}, LiteralTypes: []string{"string"}},
+				{Column: "billing_address.geo.zip", Field: "Billing.Geo.Zip", Operators: []string{"is not null"}},
+				{Column: "metadata.source", Field: "Metadata.source", Operators: []string{"="}, LiteralTypes: []string{"null"}},
+				{Column: "created_by", Field: "CreatedBy", Operators: []string{"="}, LiteralTypes: []string{"string"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "map的键区分大小写",
+			model:       map[string]interface{}{"Name": "张三", "name": "李四"},
+			whereClause: "Name = 'a' AND name LIKE 'b%' AND NAME IS NULL",
+			want: []FieldRef{
+				{Column: "Name", Field: "Name", Operators: []string{"="}, LiteralTypes: []string{"string"}},
+				{Column: "name", Field: "name", Operators: []string{"like"}, LiteralTypes: []string{"string"}},
+				{Column: "NAME", Field: "NAME", Operators: []string{"is null"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "FieldGetter模型",
+			model:       &Profile{},
+			whereClause: "age > 18 AND profiles.address.city = 'Beijing' AND age < 60",
+			want: []FieldRef{
+				{Column: "age", Field: "age", Operators: []string{">", "<"}, LiteralTypes: []string{"number"}},
+				{Column: "profiles.address.city", Field: "address.city", Operators: []string{"="}, LiteralTypes: []string{"string"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "FieldGetter模型的列不存在",
+			model:       &Profile{},
+			whereClause: "age > 18 AND agee > 20",
+			wantErr:     true,
+		},
+		{
+			name:        "字段未找到",
+			model:       &UserWithNonPtr{},
+			whereClause: "age > 18 OR agee > 20",
+			wantErr:     true,
+		},
+		{
+			name:        "语法错误",
+			model:       &UserWithNonPtr{},
+			whereClause: "age > > 18",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSQLEvaluator(tt.model).Fields(tt.whereClause)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Fields() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Fields() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompiledWhereFields(t *testing.T) {
+	compiled := MustCompile("age > 18 AND agee > 20")
+
+	// 没有模型时不解析Go字段
+	got, err := compiled.Fields(nil)
+	want := []FieldRef{
+		{Column: "age", Operators: []string{">"}, LiteralTypes: []string{"number"}},
+		{Column: "agee", Operators: []string{">"}, LiteralTypes: []string{"number"}},
+	}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields(nil) = %+v, %v, want %+v", got, err, want)
+	}
+
+	_, err = compiled.Fields(&UserWithNonPtr{})
+	var notFound *FieldNotFoundError
+	if !errors.As(err, &notFound) || notFound.Pos != 13 {
+		t.Errorf("Fields() error = %v, want *FieldNotFoundError at 13", err)
+	}
+}
@@ -0,0 +1,490 @@
+package sqlevaluator
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// substringFuncName 改写后的SUBSTRING函数名，sqlparser的SUBSTRING语法只接受列名作为第一个参数
+const substringFuncName = "substring"
+
+// builtinFunction 内置标量函数
+type builtinFunction struct {
+	// minArgs、maxArgs 参数个数范围，maxArgs 为-1表示不限
+	minArgs, maxArgs int
+	// nullSafe 为true时由函数自行处理NULL参数，否则任一参数为NULL时结果为NULL（与MySQL一致）
+	nullSafe bool
+	call     func(e *SQLEvaluator, args []interface{}) (interface{}, error)
+}
+
+// builtinFunctions 内置标量函数，键为小写的函数名
+var builtinFunctions = map[string]builtinFunction{
+	// 字符串函数
+	"lower":            {minArgs: 1, maxArgs: 1, call: funcLower},
+	"lcase":            {minArgs: 1, maxArgs: 1, call: funcLower},
+	"upper":            {minArgs: 1, maxArgs: 1, call: funcUpper},
+	"ucase":            {minArgs: 1, maxArgs: 1, call: funcUpper},
+	"trim":             {minArgs: 1, maxArgs: 1, call: funcTrim},
+	"ltrim":            {minArgs: 1, maxArgs: 1, call: funcLTrim},
+	"rtrim":            {minArgs: 1, maxArgs: 1, call: funcRTrim},
+	"concat":           {minArgs: 1, maxArgs: -1, call: funcConcat},
+	"substring":        {minArgs: 2, maxArgs: 3, call: funcSubstring},
+	"substr":           {minArgs: 2, maxArgs: 3, call: funcSubstring},
+	"mid":              {minArgs: 3, maxArgs: 3, call: funcSubstring},
+	"length":           {minArgs: 1, maxArgs: 1, call: funcLength},
+	"octet_length":     {minArgs: 1, maxArgs: 1, call: funcLength},
+	"char_length":      {minArgs: 1, maxArgs: 1, call: funcCharLength},
+	"character_length": {minArgs: 1, maxArgs: 1, call: funcCharLength},
+	"replace":          {minArgs: 3, maxArgs: 3, call: funcReplace},
+	"left":             {minArgs: 2, maxArgs: 2, call: funcLeft},
+	"right":            {minArgs: 2, maxArgs: 2, call: funcRight},
+
+	// 数学函数
+	"abs":     {minArgs: 1, maxArgs: 1, call: funcAbs},
+	"ceil":    {minArgs: 1, maxArgs: 1, call: funcCeil},
+	"ceiling": {minArgs: 1, maxArgs: 1, call: funcCeil},
+	"floor":   {minArgs: 1, maxArgs: 1, call: funcFloor},
+	"round":   {minArgs: 1, maxArgs: 2, call: funcRound},
+	"mod":     {minArgs: 2, maxArgs: 2, call: funcMod},
+	"pow":     {minArgs: 2, maxArgs: 2, call: funcPow},
+	"power":   {minArgs: 2, maxArgs: 2, call: funcPow},
+
+	// NULL处理函数
+	"coalesce": {minArgs: 1, maxArgs: -1, nullSafe: true, call: funcCoalesce},
+	"ifnull":   {minArgs: 2, maxArgs: 2, nullSafe: true, call: funcCoalesce},
+	"nullif":   {minArgs: 2, maxArgs: 2, nullSafe: true, call: funcNullIf},
+	"if":       {minArgs: 3, maxArgs: 3, nullSafe: true, call: funcIf},
+}
+
+// evaluateFuncExpr 评估函数调用，如 LOWER(name)、COALESCE(nickname, name)
+//
+// 函数名、参数个数和参数形式已在编译时由 resolveFunctions 检查。
+func (e *SQLEvaluator) evaluateFuncExpr(expr *sqlparser.FuncExpr) (interface{}, error) {
+	name := expr.Name.Lowered()
+	if f, ok := e.compiled.functions[name]; ok {
+		return e.callUserFunction(f, expr)
+	}
+	fn, ok := builtinFunctions[name]
+	if !ok {
+		return nil, newUnsupported(constructFunction, sqlparser.String(expr.Name))
+	}
+
+	args := make([]interface{}, 0, len(expr.Exprs))
+	for _, selectExpr := range expr.Exprs {
+		value, err := e.getValue(selectExpr.(*sqlparser.AliasedExpr).Expr)
+		if err != nil {
+			return nil, err
+		}
+		// 任一参数为NULL时结果为NULL
+		if value == nil && !fn.nullSafe {
+			return nil, nil
+		}
+		args = append(args, value)
+	}
+
+	return fn.call(e, args)
+}
+
+// evaluateSubstrExpr 评估sqlparser原生解析的 SUBSTRING(column, pos, len) 表达式
+func (e *SQLEvaluator) evaluateSubstrExpr(expr *sqlparser.SubstrExpr) (interface{}, error) {
+	argExprs := []sqlparser.Expr{expr.Name, expr.From}
+	if expr.To != nil {
+		argExprs = append(argExprs, expr.To)
+	}
+
+	args := make([]interface{}, 0, len(argExprs))
+	for _, argExpr := range argExprs {
+		value, err := e.getValue(argExpr)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			return nil, nil
+		}
+		args = append(args, value)
+	}
+
+	return funcSubstring(e, args)
+}
+
+// toString 将值转换为字符串参数
+func toString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case *big.Rat:
+		return ratString(v), nil
+	case bool:
+		// 与MySQL一致，布尔值作为1和0处理
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05"), nil
+	default:
+		return "", newConversionError(value, typeString, "")
+	}
+}
+
+// stringArgs 将参数转换为字符串
+func stringArgs(args []interface{}) ([]string, error) {
+	result := make([]string, 0, len(args))
+	for _, arg := range args {
+		s, err := toString(arg)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// intArg 将参数转换为整数，浮点数四舍五入
+func intArg(value interface{}) (int, error) {
+	number, err := toNumber(value)
+	if err != nil {
+		return 0, err
+	}
+	return int(toInt64(number)), nil
+}
+
+// funcLower LOWER(str)
+func funcLower(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	s, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return strings.ToLower(s), nil
+}
+
+// funcUpper UPPER(str)
+func funcUpper(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	s, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return strings.ToUpper(s), nil
+}
+
+// funcTrim TRIM(str)，去除两端的空格
+func funcTrim(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	s, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return strings.Trim(s, " "), nil
+}
+
+// funcLTrim LTRIM(str)
+func funcLTrim(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	s, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return strings.TrimLeft(s, " "), nil
+}
+
+// funcRTrim RTRIM(str)
+func funcRTrim(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	s, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return strings.TrimRight(s, " "), nil
+}
+
+// funcConcat CONCAT(str1, str2, ...)
+func funcConcat(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	strs, err := stringArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Join(strs, ""), nil
+}
+
+// funcSubstring SUBSTRING(str, pos[, len])，按字符计数，pos从1开始，负数表示从末尾倒数
+func funcSubstring(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	s, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+	pos, err := intArg(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	runes := []rune(s)
+	switch {
+	case pos > 0:
+		pos--
+	case pos < 0:
+		pos += len(runes)
+	default:
+		return "", nil
+	}
+	if pos < 0 || pos >= len(runes) {
+		return "", nil
+	}
+
+	end := len(runes)
+	if len(args) > 2 {
+		length, err := intArg(args[2])
+		if err != nil {
+			return nil, err
+		}
+		if length <= 0 {
+			return "", nil
+		}
+		if pos+length < end {
+			end = pos + length
+		}
+	}
+	return string(runes[pos:end]), nil
+}
+
+// funcLength LENGTH(str)，返回字节数
+func funcLength(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	s, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return int64(len(s)), nil
+}
+
+// funcCharLength CHAR_LENGTH(str)，返回字符数
+func funcCharLength(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	s, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return int64(utf8.RuneCountInString(s)), nil
+}
+
+// funcReplace REPLACE(str, from, to)
+func funcReplace(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	strs, err := stringArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	// 与MySQL一致，查找空字符串时不做替换
+	if strs[1] == "" {
+		return strs[0], nil
+	}
+	return strings.ReplaceAll(strs[0], strs[1], strs[2]), nil
+}
+
+// funcLeft LEFT(str, len)
+func funcLeft(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	s, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+	length, err := intArg(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	runes := []rune(s)
+	if length <= 0 {
+		return "", nil
+	}
+	if length > len(runes) {
+		length = len(runes)
+	}
+	return string(runes[:length]), nil
+}
+
+// funcRight RIGHT(str, len)
+func funcRight(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	s, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+	length, err := intArg(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	runes := []rune(s)
+	if length <= 0 {
+		return "", nil
+	}
+	if length > len(runes) {
+		length = len(runes)
+	}
+	return string(runes[len(runes)-length:]), nil
+}
+
+// funcAbs ABS(x)
+func funcAbs(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	number, err := toNumber(args[0])
+	if err != nil {
+		return nil, err
+	}
+	switch v := number.(type) {
+	case int64:
+		if v < 0 {
+			return negate(v)
+		}
+		return v, nil
+	case uint64:
+		return v, nil
+	case *big.Rat:
+		return new(big.Rat).Abs(v), nil
+	default:
+		return math.Abs(v.(float64)), nil
+	}
+}
+
+// funcCeil CEIL(x)
+func funcCeil(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	number, err := toNumber(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if isInteger(number) {
+		return number, nil
+	}
+	if r, ok := number.(*big.Rat); ok {
+		return fromBigInt(ceilRat(r))
+	}
+	return math.Ceil(number.(float64)), nil
+}
+
+// funcFloor FLOOR(x)
+func funcFloor(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	number, err := toNumber(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if isInteger(number) {
+		return number, nil
+	}
+	if r, ok := number.(*big.Rat); ok {
+		return fromBigInt(floorRat(r))
+	}
+	return math.Floor(number.(float64)), nil
+}
+
+// funcRound ROUND(x[, d])，四舍五入到d位小数，d为负数时对整数部分取整，0.5向远离零的方向舍入
+func funcRound(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	number, err := toNumber(args[0])
+	if err != nil {
+		return nil, err
+	}
+	digits := 0
+	if len(args) > 1 {
+		digits, err = intArg(args[1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if isInteger(number) {
+		if digits >= 0 {
+			return number, nil
+		}
+		return roundInteger(toBigInt(number), -digits)
+	}
+	if r, ok := number.(*big.Rat); ok {
+		return roundRat(r, digits), nil
+	}
+
+	scale := math.Pow(10, float64(digits))
+	return math.Round(number.(float64)*scale) / scale, nil
+}
+
+// roundInteger 将整数四舍五入到10^digits的倍数，0.5向远离零的方向舍入
+func roundInteger(i *big.Int, digits int) (interface{}, error) {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+	quotient, remainder := new(big.Int).QuoRem(i, scale, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(scale) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(i.Sign())))
+	}
+	return fromBigInt(quotient.Mul(quotient, scale))
+}
+
+// funcMod MOD(n, m)，m为0时结果为NULL
+func funcMod(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	left, err := toNumber(args[0])
+	if err != nil {
+		return nil, err
+	}
+	right, err := toNumber(args[1])
+	if err != nil {
+		return nil, err
+	}
+	return arithmetic(sqlparser.ModStr, left, right)
+}
+
+// funcPow POW(x, y)，结果不是有限数时为NULL
+func funcPow(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	base, err := toNumber(args[0])
+	if err != nil {
+		return nil, err
+	}
+	exponent, err := toNumber(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	result := math.Pow(toFloat(base), toFloat(exponent))
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// funcCoalesce COALESCE(value, ...) 和 IFNULL(expr1, expr2)，返回第一个非NULL的参数
+func funcCoalesce(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	for _, arg := range args {
+		if arg != nil {
+			return arg, nil
+		}
+	}
+	return nil, nil
+}
+
+// funcNullIf NULLIF(expr1, expr2)，两个参数相等时返回NULL，否则返回expr1
+func funcNullIf(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	if args[0] == nil || args[1] == nil {
+		return args[0], nil
+	}
+
+	left, right, err := e.convertTypes(args[0], args[1], sqlparser.EqualStr)
+	if err != nil {
+		return nil, err
+	}
+	if valuesEqual(left, right) {
+		return nil, nil
+	}
+	return args[0], nil
+}
+
+// funcIf IF(expr1, expr2, expr3)，expr1为真时返回expr2，为假或NULL时返回expr3
+func funcIf(e *SQLEvaluator, args []interface{}) (interface{}, error) {
+	if args[0] == nil {
+		return args[2], nil
+	}
+	condition, err := toBool(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if condition {
+		return args[1], nil
+	}
+	return args[2], nil
+}
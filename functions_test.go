@@ -0,0 +1,347 @@
+package sqlevaluator
+
+import "testing"
+
+// Member 函数测试使用的会员模型
+type Member struct {
+	Name     string   `json:"name"`
+	Nickname *string  `json:"nickname"`
+	Email    string   `json:"email"`
+	Balance  float64  `json:"balance"`
+	Salary   *float64 `json:"salary"`
+	Points   int      `json:"points"`
+}
+
+func TestSQLEvaluatorFunctions(t *testing.T) {
+	member := &Member{
+		Name:    "Alice",
+		Email:   "  Alice@Example.com ",
+		Balance: -7.5,
+		Salary:  float64Ptr(4999.6),
+		Points:  17,
+	}
+	chinese := &Member{
+		Name:     "张三丰",
+		Nickname: strPtr("三丰"),
+		Points:   -3,
+	}
+
+	tests := []struct {
+		name        string
+		model       interface{}
+		whereClause string
+		want        bool
+		wantErr     bool
+	}{
+		// 字符串函数
+		{
+			name:        "LOWER",
+			model:       member,
+			whereClause: "LOWER(name) = 'alice'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "UPPER",
+			model:       member,
+			whereClause: "UPPER(name) = 'ALICE'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "TRIM",
+			model:       member,
+			whereClause: "TRIM(email) = 'Alice@Example.com'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "函数嵌套",
+			model:       member,
+			whereClause: "LOWER(TRIM(email)) LIKE '%@example.com'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "CONCAT",
+			model:       member,
+			whereClause: "CONCAT(name, '-', points) = 'Alice-17'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "CONCAT参数为NULL",
+			model:       member,
+			whereClause: "CONCAT(name, nickname) IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "SUBSTRING列名",
+			model:       member,
+			whereClause: "SUBSTRING(name, 2, 3) = 'lic'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "SUBSTRING FROM FOR",
+			model:       member,
+			whereClause: "SUBSTRING(name FROM 2 FOR 2) = 'li'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "SUBSTRING表达式参数",
+			model:       member,
+			whereClause: "SUBSTRING(LOWER(name), 1, 1) = 'a'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "SUBSTRING负数位置",
+			model:       member,
+			whereClause: "SUBSTR(name, -3) = 'ice'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "SUBSTRING按字符计数",
+			model:       chinese,
+			whereClause: "SUBSTRING(name, 2) = '三丰'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "LENGTH按字节计数",
+			model:       chinese,
+			whereClause: "LENGTH(name) = 9",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "CHAR_LENGTH按字符计数",
+			model:       chinese,
+			whereClause: "CHAR_LENGTH(name) = 3",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "REPLACE",
+			model:       member,
+			whereClause: "REPLACE(name, 'A', 'a') = 'alice'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "LEFT",
+			model:       chinese,
+			whereClause: "LEFT(name, 1) = '张'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "RIGHT",
+			model:       member,
+			whereClause: "RIGHT(name, 10) = 'Alice'",
+			want:        true,
+			wantErr:     false,
+		},
+
+		// 数学函数
+		{
+			name:        "ABS",
+			model:       member,
+			whereClause: "ABS(balance) < 10",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "ABS整数",
+			model:       chinese,
+			whereClause: "ABS(points) = 3",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "CEIL",
+			model:       member,
+			whereClause: "CEIL(balance) = -7",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "FLOOR",
+			model:       member,
+			whereClause: "FLOOR(balance) = -8",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "ROUND",
+			model:       member,
+			whereClause: "ROUND(salary, 0) = 5000",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "ROUND默认位数",
+			model:       member,
+			whereClause: "ROUND(balance) = -8",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "ROUND负数位数",
+			model:       member,
+			whereClause: "ROUND(points, -1) = 20",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "MOD",
+			model:       member,
+			whereClause: "MOD(points, 5) = 2",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "MOD除数为0",
+			model:       member,
+			whereClause: "MOD(points, 0) IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "POW",
+			model:       member,
+			whereClause: "POW(points, 2) = 289",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "数学函数参与算术运算",
+			model:       member,
+			whereClause: "ABS(balance) * 2 = 15",
+			want:        true,
+			wantErr:     false,
+		},
+
+		// NULL处理函数
+		{
+			name:        "COALESCE",
+			model:       member,
+			whereClause: "COALESCE(nickname, name) = 'Alice'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "COALESCE取第一个非NULL",
+			model:       chinese,
+			whereClause: "COALESCE(nickname, name) = '三丰'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "IFNULL",
+			model:       chinese,
+			whereClause: "IFNULL(salary, 0) = 0",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "NULLIF相等",
+			model:       member,
+			whereClause: "NULLIF(points, 17) IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "NULLIF不相等",
+			model:       member,
+			whereClause: "NULLIF(points, 18) = 17",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "IF条件为真",
+			model:       member,
+			whereClause: "IF(points > 10, 'high', 'low') = 'high'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "IF条件为NULL",
+			model:       chinese,
+			whereClause: "IF(salary > 0, 'paid', 'unpaid') = 'unpaid'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "字符串函数参数为NULL",
+			model:       member,
+			whereClause: "LOWER(nickname) = ''",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "函数单独作为条件",
+			model:       member,
+			whereClause: "IF(points > 10, 1, 0)",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "函数在IN列表中",
+			model:       member,
+			whereClause: "'alice' IN (LOWER(name), 'bob')",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "函数作为BETWEEN边界",
+			model:       member,
+			whereClause: "points BETWEEN ABS(balance) AND 20",
+			want:        true,
+			wantErr:     false,
+		},
+
+		// 错误
+		{
+			name:        "未知函数",
+			model:       member,
+			whereClause: "UNKNOWN_FUNC(name) = 'a'",
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "参数个数错误",
+			model:       member,
+			whereClause: "LOWER(name, email) = 'a'",
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "数学函数参数不是数值",
+			model:       member,
+			whereClause: "ABS(name) > 0",
+			want:        false,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(tt.model)
+			got, err := evaluator.EvaluateWhere(tt.whereClause)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
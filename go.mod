@@ -2,4 +2,4 @@ module github.com/wizizm/sql-evaluator
 
 go 1.21
 
-require github.com/xwb1989/sqlparser v0.0.0-20180606152119-120387863bf2 // indirect
+require github.com/xwb1989/sqlparser v0.0.0-20180606152119-120387863bf2
@@ -0,0 +1,231 @@
+package sqlevaluator
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// defaultLikeEscape LIKE模式默认的转义字符（与MySQL一致）
+const defaultLikeEscape = '\\'
+
+// likeElemKind LIKE模式元素的类型
+type likeElemKind int
+
+const (
+	// likeLiteral 原样匹配的字符串
+	likeLiteral likeElemKind = iota
+	// likeAnyOne 通配符 _，匹配任意一个字符
+	likeAnyOne
+	// likeAnyMany 通配符 %，匹配任意多个字符
+	likeAnyMany
+)
+
+// likeElem LIKE模式的元素
+type likeElem struct {
+	kind likeElemKind
+	text string
+}
+
+// likePattern 编译后的LIKE模式
+type likePattern struct {
+	elems []likeElem
+	// fold 为true时不区分大小写，原样匹配的字符串已转换为小写
+	fold bool
+}
+
+// compileLike 编译LIKE模式，escape 之后的字符按原样匹配，escape 为-1时不使用转义字符
+//
+// 除 % 和 _ 以外的字符都按原样匹配；模式末尾的转义字符匹配它自身（与MySQL一致）。
+func compileLike(pattern string, escape rune) *likePattern {
+	p := &likePattern{}
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			p.elems = append(p.elems, likeElem{kind: likeLiteral, text: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == escape && i+1 < len(runes):
+			i++
+			literal.WriteRune(runes[i])
+		case r == '%':
+			flush()
+			// 连续的 % 等价于一个
+			if n := len(p.elems); n == 0 || p.elems[n-1].kind != likeAnyMany {
+				p.elems = append(p.elems, likeElem{kind: likeAnyMany})
+			}
+		case r == '_':
+			flush()
+			p.elems = append(p.elems, likeElem{kind: likeAnyOne})
+		default:
+			literal.WriteRune(r)
+		}
+	}
+	flush()
+	return p
+}
+
+// foldCase 将模式转换为不区分大小写的形式
+func (p *likePattern) foldCase() *likePattern {
+	p.fold = true
+	for i := range p.elems {
+		p.elems[i].text = strings.ToLower(p.elems[i].text)
+	}
+	return p
+}
+
+// match 判断字符串是否匹配LIKE模式
+//
+// 遇到 % 时记录回溯点，后续元素匹配失败时让 % 多匹配一个字符再重试。
+func (p *likePattern) match(s string) bool {
+	if p.fold {
+		s = strings.ToLower(s)
+	}
+
+	pi, si := 0, 0
+	starPi, starSi := -1, 0
+	for {
+		if pi < len(p.elems) {
+			elem := p.elems[pi]
+			switch elem.kind {
+			case likeAnyMany:
+				// 模式以 % 结尾时剩余部分一定匹配
+				if pi == len(p.elems)-1 {
+					return true
+				}
+				starPi, starSi = pi, si
+				pi++
+				continue
+			case likeAnyOne:
+				if si < len(s) {
+					_, size := utf8.DecodeRuneInString(s[si:])
+					si += size
+					pi++
+					continue
+				}
+			case likeLiteral:
+				if strings.HasPrefix(s[si:], elem.text) {
+					si += len(elem.text)
+					pi++
+					continue
+				}
+			}
+		} else if si == len(s) {
+			return true
+		}
+
+		// 回溯到最近的 %，让它多匹配一个字符
+		if starPi < 0 || starSi >= len(s) {
+			return false
+		}
+		_, size := utf8.DecodeRuneInString(s[starSi:])
+		starSi += size
+		pi, si = starPi+1, starSi
+	}
+}
+
+// isLikeOperator 判断是否为LIKE或NOT LIKE操作符
+func isLikeOperator(operator string) bool {
+	return operator == sqlparser.LikeStr || operator == sqlparser.NotLikeStr
+}
+
+// evaluateLike 评估LIKE操作符，两侧的值都按字符串匹配
+//
+// 模式为字面量时使用编译时缓存的结果，否则每次评估时编译。
+func (e *SQLEvaluator) evaluateLike(expr *sqlparser.ComparisonExpr, left, right interface{}) (bool, error) {
+	leftStr, err := toString(left)
+	if err != nil {
+		return false, withNode(newConversionError(left, typeString, expr.Operator), expr.Left)
+	}
+
+	pattern, ok := e.compiled.likePatterns[expr]
+	if !ok {
+		rightStr, err := toString(right)
+		if err != nil {
+			return false, withNode(newConversionError(right, typeString, expr.Operator), expr.Right)
+		}
+		escape, err := e.likeEscape(expr)
+		if err != nil {
+			return false, err
+		}
+		pattern = newLikePattern(rightStr, escape, e.options)
+	}
+
+	return pattern.match(leftStr), nil
+}
+
+// likeEscape 返回LIKE表达式的转义字符，未指定ESCAPE时为反斜杠，ESCAPE为空字符串时不使用转义字符
+func (e *SQLEvaluator) likeEscape(expr *sqlparser.ComparisonExpr) (rune, error) {
+	if expr.Escape == nil {
+		return defaultLikeEscape, nil
+	}
+
+	value, err := e.getValue(expr.Escape)
+	if err != nil {
+		return 0, err
+	}
+	if value == nil {
+		return defaultLikeEscape, nil
+	}
+	escape, ok := value.(string)
+	if !ok || utf8.RuneCountInString(escape) > 1 {
+		return 0, newInvalidArgument(reasonEscape, fmt.Sprint(value), nil, msgEscape, value)
+	}
+	if escape == "" {
+		return -1, nil
+	}
+	r, _ := utf8.DecodeRuneInString(escape)
+	return r, nil
+}
+
+// newLikePattern 按评估配置编译LIKE模式
+func newLikePattern(pattern string, escape rune, opts options) *likePattern {
+	p := compileLike(pattern, escape)
+	if opts.caseInsensitiveLike {
+		p.foldCase()
+	}
+	return p
+}
+
+// compileLikePatterns 预编译子句中模式和转义字符都是字面量的LIKE表达式
+func compileLikePatterns(expr sqlparser.Expr, opts options) (map[*sqlparser.ComparisonExpr]*likePattern, error) {
+	var patterns map[*sqlparser.ComparisonExpr]*likePattern
+	e := &SQLEvaluator{}
+	err := sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		comparison, ok := node.(*sqlparser.ComparisonExpr)
+		if !ok || !isLikeOperator(comparison.Operator) || !isStringLiteral(comparison.Right) {
+			return true, nil
+		}
+		if comparison.Escape != nil && !isStringLiteral(comparison.Escape) {
+			return true, nil
+		}
+
+		escape, err := e.likeEscape(comparison)
+		if err != nil {
+			return false, withNode(err, comparison)
+		}
+		if patterns == nil {
+			patterns = make(map[*sqlparser.ComparisonExpr]*likePattern)
+		}
+		patterns[comparison] = newLikePattern(string(comparison.Right.(*sqlparser.SQLVal).Val), escape, opts)
+		return true, nil
+	}, expr)
+	if err != nil {
+		return nil, err
+	}
+	return patterns, nil
+}
+
+// isStringLiteral 判断表达式是否为字符串字面量
+func isStringLiteral(expr sqlparser.Expr) bool {
+	val, ok := expr.(*sqlparser.SQLVal)
+	return ok && val.Type == sqlparser.StrVal
+}
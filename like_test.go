@@ -0,0 +1,242 @@
+package sqlevaluator
+
+import "testing"
+
+func TestLikePattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		escape  rune
+		input   string
+		want    bool
+	}{
+		{
+			name:    "前缀匹配",
+			pattern: "张%",
+			escape:  defaultLikeEscape,
+			input:   "张三",
+			want:    true,
+		},
+		{
+			name:    "单字符通配符按字符计数",
+			pattern: "张_",
+			escape:  defaultLikeEscape,
+			input:   "张三",
+			want:    true,
+		},
+		{
+			name:    "单字符通配符不匹配空字符",
+			pattern: "a_",
+			escape:  defaultLikeEscape,
+			input:   "a",
+			want:    false,
+		},
+		{
+			name:    "正则元字符按原样匹配",
+			pattern: "a.b%",
+			escape:  defaultLikeEscape,
+			input:   "axb",
+			want:    false,
+		},
+		{
+			name:    "正则元字符匹配自身",
+			pattern: "a.b%",
+			escape:  defaultLikeEscape,
+			input:   "a.bc",
+			want:    true,
+		},
+		{
+			name:    "加号和括号",
+			pattern: "50%+(x)",
+			escape:  defaultLikeEscape,
+			input:   "50 off +(x)",
+			want:    true,
+		},
+		{
+			name:    "转义百分号",
+			pattern: `50\%`,
+			escape:  defaultLikeEscape,
+			input:   "50%",
+			want:    true,
+		},
+		{
+			name:    "转义百分号不是通配符",
+			pattern: `50\%`,
+			escape:  defaultLikeEscape,
+			input:   "500",
+			want:    false,
+		},
+		{
+			name:    "自定义转义字符",
+			pattern: "a|_b%",
+			escape:  '|',
+			input:   "a_bc",
+			want:    true,
+		},
+		{
+			name:    "自定义转义字符时反斜杠按原样匹配",
+			pattern: `a\%`,
+			escape:  '|',
+			input:   `a\xyz`,
+			want:    true,
+		},
+		{
+			name:    "末尾的转义字符匹配自身",
+			pattern: `a\`,
+			escape:  defaultLikeEscape,
+			input:   `a\`,
+			want:    true,
+		},
+		{
+			name:    "不使用转义字符",
+			pattern: `a\%`,
+			escape:  -1,
+			input:   `a\b`,
+			want:    true,
+		},
+		{
+			name:    "中间的百分号需要回溯",
+			pattern: "%ab%abc",
+			escape:  defaultLikeEscape,
+			input:   "xabyabab abc",
+			want:    true,
+		},
+		{
+			name:    "多个百分号和单字符通配符",
+			pattern: "%a_c%d",
+			escape:  defaultLikeEscape,
+			input:   "zzabcxd",
+			want:    true,
+		},
+		{
+			name:    "空模式只匹配空字符串",
+			pattern: "",
+			escape:  defaultLikeEscape,
+			input:   "a",
+			want:    false,
+		},
+		{
+			name:    "只有百分号",
+			pattern: "%%",
+			escape:  defaultLikeEscape,
+			input:   "",
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compileLike(tt.pattern, tt.escape).match(tt.input); got != tt.want {
+				t.Errorf("match(%q, %q) = %v, want %v", tt.pattern, tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLEvaluatorLike(t *testing.T) {
+	product := map[string]interface{}{
+		"name":     "a.b (50%+)",
+		"code":     "A_1",
+		"price":    1999,
+		"pattern":  "a.b%",
+		"nickname": nil,
+	}
+
+	tests := []struct {
+		name        string
+		whereClause string
+		args        []interface{}
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "点号按原样匹配",
+			whereClause: "name LIKE 'a.b%'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "反斜杠转义百分号",
+			whereClause: `name LIKE '%50\%+)'`,
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "反斜杠转义下划线",
+			whereClause: `code LIKE 'A\_%' AND code NOT LIKE 'AB\_%'`,
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "ESCAPE子句",
+			whereClause: "code LIKE 'A!_1' ESCAPE '!'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "ESCAPE子句后转义的下划线不是通配符",
+			whereClause: "'AX1' LIKE 'A!_1' ESCAPE '!'",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "模式来自字段",
+			whereClause: "name LIKE pattern",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "模式来自参数",
+			whereClause: "name LIKE ?",
+			args:        []interface{}{"%(50\\%+)"},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "数值按字符串匹配",
+			whereClause: "price LIKE '19%'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "NULL不匹配",
+			whereClause: "nickname LIKE '%' OR nickname NOT LIKE '%'",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "ESCAPE不是单个字符",
+			whereClause: "code LIKE 'A!_1' ESCAPE '!!'",
+			want:        false,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(product)
+			got, err := evaluator.EvaluateWhere(tt.whereClause, tt.args...)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkLike(b *testing.B) {
+	rule := MustCompile("name LIKE '%zhang%san%' OR name LIKE 'li\\_%'")
+	user := &UserWithNonPtr{Name: "wang wu zhang xiao san"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := rule.Evaluate(user); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
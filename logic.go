@@ -0,0 +1,82 @@
+package sqlevaluator
+
+// truth SQL条件的真值，除TRUE和FALSE外还包括与NULL比较得到的UNKNOWN
+type truth int8
+
+const (
+	truthFalse truth = iota
+	truthTrue
+	truthUnknown
+)
+
+// truthOf 将布尔值转换为真值
+func truthOf(b bool) truth {
+	if b {
+		return truthTrue
+	}
+	return truthFalse
+}
+
+// not 逻辑取反，NOT UNKNOWN 仍为 UNKNOWN
+func (t truth) not() truth {
+	switch t {
+	case truthTrue:
+		return truthFalse
+	case truthFalse:
+		return truthTrue
+	default:
+		return truthUnknown
+	}
+}
+
+// and 逻辑与，任一操作数为FALSE时结果为FALSE
+func (t truth) and(other truth) truth {
+	if t == truthFalse || other == truthFalse {
+		return truthFalse
+	}
+	if t == truthUnknown || other == truthUnknown {
+		return truthUnknown
+	}
+	return truthTrue
+}
+
+// or 逻辑或，任一操作数为TRUE时结果为TRUE
+func (t truth) or(other truth) truth {
+	if t == truthTrue || other == truthTrue {
+		return truthTrue
+	}
+	if t == truthUnknown || other == truthUnknown {
+		return truthUnknown
+	}
+	return truthFalse
+}
+
+// xor 逻辑异或，任一操作数为UNKNOWN时结果为UNKNOWN
+func (t truth) xor(other truth) truth {
+	if t == truthUnknown || other == truthUnknown {
+		return truthUnknown
+	}
+	return truthOf(t != other)
+}
+
+// String 返回真值的SQL表示
+func (t truth) String() string {
+	switch t {
+	case truthTrue:
+		return "TRUE"
+	case truthFalse:
+		return "FALSE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// nullResult 返回涉及NULL的比较结果
+//
+// 三值逻辑模式下为UNKNOWN，否则沿用原有行为返回FALSE。
+func (e *SQLEvaluator) nullResult() truth {
+	if e.options.threeValuedLogic {
+		return truthUnknown
+	}
+	return truthFalse
+}
@@ -0,0 +1,398 @@
+package sqlevaluator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTruthOperators(t *testing.T) {
+	values := []truth{truthTrue, truthFalse, truthUnknown}
+	tests := []struct {
+		name string
+		op   func(a, b truth) truth
+		want [3][3]truth
+	}{
+		{
+			name: "AND",
+			op:   truth.and,
+			want: [3][3]truth{
+				{truthTrue, truthFalse, truthUnknown},
+				{truthFalse, truthFalse, truthFalse},
+				{truthUnknown, truthFalse, truthUnknown},
+			},
+		},
+		{
+			name: "OR",
+			op:   truth.or,
+			want: [3][3]truth{
+				{truthTrue, truthTrue, truthTrue},
+				{truthTrue, truthFalse, truthUnknown},
+				{truthTrue, truthUnknown, truthUnknown},
+			},
+		},
+		{
+			name: "XOR",
+			op:   truth.xor,
+			want: [3][3]truth{
+				{truthFalse, truthTrue, truthUnknown},
+				{truthTrue, truthFalse, truthUnknown},
+				{truthUnknown, truthUnknown, truthUnknown},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, a := range values {
+				for j, b := range values {
+					if got := tt.op(a, b); got != tt.want[i][j] {
+						t.Errorf("%v %s %v = %v, want %v", a, tt.name, b, got, tt.want[i][j])
+					}
+				}
+			}
+		})
+	}
+
+	if truthUnknown.not() != truthUnknown || truthTrue.not() != truthFalse || truthFalse.not() != truthTrue {
+		t.Errorf("NOT 真值表错误")
+	}
+}
+
+// TestSQLEvaluatorThreeValuedLogic 对比默认模式与三值逻辑模式下涉及NULL的结果
+func TestSQLEvaluatorThreeValuedLogic(t *testing.T) {
+	nullUser := &User{
+		ID:  intPtr(1),
+		Age: intPtr(25),
+	}
+
+	tests := []struct {
+		name            string
+		model           interface{}
+		whereClause     string
+		want            bool
+		wantThreeValued bool
+		wantErr         bool
+	}{
+		{
+			name:            "NOT与NULL比较",
+			model:           nullUser,
+			whereClause:     "NOT (name = '张三')",
+			want:            true,
+			wantThreeValued: false,
+		},
+		{
+			name:            "不等于NULL字段",
+			model:           nullUser,
+			whereClause:     "NOT (name != '张三')",
+			want:            true,
+			wantThreeValued: false,
+		},
+		{
+			name:            "与NULL字面量比较",
+			model:           nullUser,
+			whereClause:     "NOT (age = NULL)",
+			want:            true,
+			wantThreeValued: false,
+		},
+		{
+			name:            "NOT IN列表包含NULL",
+			model:           nullUser,
+			whereClause:     "age NOT IN (1, NULL)",
+			want:            true,
+			wantThreeValued: false,
+		},
+		{
+			name:            "IN列表包含NULL且匹配",
+			model:           nullUser,
+			whereClause:     "age IN (25, NULL)",
+			want:            true,
+			wantThreeValued: true,
+		},
+		{
+			name:            "NOT IN列表包含NULL且匹配",
+			model:           nullUser,
+			whereClause:     "NOT (age NOT IN (25, NULL))",
+			want:            true,
+			wantThreeValued: true,
+		},
+		{
+			name:            "NULL字段NOT IN",
+			model:           nullUser,
+			whereClause:     "NOT (salary NOT IN (1, 2))",
+			want:            true,
+			wantThreeValued: false,
+		},
+		{
+			name:            "UNKNOWN OR TRUE",
+			model:           nullUser,
+			whereClause:     "name = '张三' OR age = 25",
+			want:            true,
+			wantThreeValued: true,
+		},
+		{
+			name:            "NOT (UNKNOWN AND FALSE)",
+			model:           nullUser,
+			whereClause:     "NOT (name = '张三' AND age = 30)",
+			want:            true,
+			wantThreeValued: true,
+		},
+		{
+			name:            "NOT (UNKNOWN OR FALSE)",
+			model:           nullUser,
+			whereClause:     "NOT (name = '张三' OR age = 30)",
+			want:            true,
+			wantThreeValued: false,
+		},
+		{
+			name:            "BETWEEN下界为NULL但上界可判定",
+			model:           nullUser,
+			whereClause:     "age NOT BETWEEN NULL AND 10",
+			want:            false,
+			wantThreeValued: true,
+		},
+		{
+			name:            "BETWEEN下界为NULL且上界无法判定",
+			model:           nullUser,
+			whereClause:     "NOT (age BETWEEN NULL AND 30)",
+			want:            true,
+			wantThreeValued: false,
+		},
+		{
+			name:            "NULL字段NOT BETWEEN",
+			model:           nullUser,
+			whereClause:     "salary NOT BETWEEN 1 AND 10",
+			want:            false,
+			wantThreeValued: false,
+		},
+		{
+			name:            "XOR包含UNKNOWN",
+			model:           nullUser,
+			whereClause:     "NOT (is_active XOR age = 30)",
+			want:            true,
+			wantThreeValued: false,
+		},
+		{
+			name:            "NOT NULL布尔列",
+			model:           nullUser,
+			whereClause:     "NOT is_active",
+			want:            true,
+			wantThreeValued: false,
+		},
+		{
+			name:            "UNKNOWN IS NOT TRUE",
+			model:           nullUser,
+			whereClause:     "(name = '张三') IS NOT TRUE",
+			want:            true,
+			wantThreeValued: true,
+		},
+		{
+			name:            "UNKNOWN IS FALSE",
+			model:           nullUser,
+			whereClause:     "(name = '张三') IS FALSE",
+			want:            true,
+			wantThreeValued: false,
+		},
+		{
+			name:            "IS NULL不受影响",
+			model:           nullUser,
+			whereClause:     "name IS NULL AND NOT (age IS NULL)",
+			want:            true,
+			wantThreeValued: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSQLEvaluator(tt.model).EvaluateWhere(tt.whereClause)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+
+			compiled, err := Compile(tt.whereClause, WithThreeValuedLogic())
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			got, err = compiled.Evaluate(tt.model)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.wantThreeValued {
+				t.Errorf("三值逻辑 Evaluate() = %v, want %v", got, tt.wantThreeValued)
+			}
+		})
+	}
+}
+
+func TestShortCircuit(t *testing.T) {
+	nullUser := &User{ID: intPtr(1)}
+
+	tests := []struct {
+		name        string
+		model       interface{}
+		whereClause string
+		opts        []Option
+		want        bool
+		wantCalls   int
+		wantErr     bool
+	}{
+		{
+			name:        "AND左侧为FALSE时不评估右侧",
+			model:       nullUser,
+			whereClause: "age IS NOT NULL AND count_call(age) > 0",
+			want:        false,
+			wantCalls:   0,
+			wantErr:     false,
+		},
+		{
+			name:        "OR左侧为TRUE时不评估右侧",
+			model:       nullUser,
+			whereClause: "id = 1 OR count_call(id) > 0 OR count_call(id) > 1",
+			want:        true,
+			wantCalls:   0,
+			wantErr:     false,
+		},
+		{
+			name:        "左侧不能决定结果时评估右侧",
+			model:       nullUser,
+			whereClause: "id = 1 AND count_call(id) > 0",
+			want:        true,
+			wantCalls:   1,
+			wantErr:     false,
+		},
+		{
+			name:        "左侧为UNKNOWN时评估右侧",
+			model:       nullUser,
+			whereClause: "age > 1 OR count_call(id) > 0",
+			opts:        []Option{WithThreeValuedLogic()},
+			want:        true,
+			wantCalls:   1,
+			wantErr:     false,
+		},
+		{
+			name:        "XOR总是评估所有操作数",
+			model:       nullUser,
+			whereClause: "id = 1 XOR count_call(id) > 0",
+			want:        false,
+			wantCalls:   1,
+			wantErr:     false,
+		},
+		{
+			name:        "未评估分支中的错误被忽略",
+			model:       nullUser,
+			whereClause: "id = 2 AND (agee > 1 OR id > 'abc')",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "评估到的分支中的错误",
+			model:       nullUser,
+			whereClause: "id = 1 AND agee > 1",
+			wantErr:     true,
+		},
+		{
+			name:        "严格模式下未评估分支中的错误",
+			model:       nullUser,
+			whereClause: "id = 2 AND agee > 1",
+			opts:        []Option{WithStrictTypeCheck()},
+			wantErr:     true,
+		},
+		{
+			name:        "严格模式下的有效子句",
+			model:       nullUser,
+			whereClause: "age IS NOT NULL AND count_call(age) > 0",
+			opts:        []Option{WithStrictTypeCheck()},
+			want:        false,
+			wantCalls:   0,
+			wantErr:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			evaluator := NewSQLEvaluator(tt.model, tt.opts...)
+			if err := evaluator.RegisterFunction("count_call", func(v int64) int64 {
+				calls++
+				return v
+			}); err != nil {
+				t.Fatalf("RegisterFunction() error = %v", err)
+			}
+
+			got, err := evaluator.EvaluateWhere(tt.whereClause)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("count_call 调用了%d次, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestStrictTypeCheckCompiled(t *testing.T) {
+	compiled := MustCompile("is_active = false OR name > 1 AND agee > 1", WithStrictTypeCheck())
+
+	_, err := compiled.Evaluate(&UserWithNonPtr{})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Fragment != "agee" {
+		t.Fatalf("Evaluate() error = %v, want ValidationErrors at agee", err)
+	}
+	var notFound *FieldNotFoundError
+	if !errors.As(err, &notFound) || notFound.Field != "agee" {
+		t.Errorf("Evaluate() error = %v, want *FieldNotFoundError for agee", err)
+	}
+
+	// 校验结果按模型类型缓存，map模型中的列在评估前无法确定类型
+	got, err := compiled.Evaluate(map[string]interface{}{"is_active": false})
+	if err != nil || !got {
+		t.Errorf("Evaluate() = %v, %v, want true", got, err)
+	}
+	_, err = compiled.Evaluate(&UserWithNonPtr{IsActive: true})
+	if !errors.As(err, &errs) {
+		t.Errorf("Evaluate() error = %v, want ValidationErrors", err)
+	}
+
+	// 只实现了 FieldGetter 的模型按 GetField 返回的值校验，同一类型的不同实例不共享校验结果
+	getterCompiled := MustCompile("level LIKE 'g%'", WithStrictTypeCheck())
+	if _, err := getterCompiled.Evaluate(rowGetter{"level": nil}); err != nil {
+		t.Errorf("Evaluate() NULL值 error = %v, want nil", err)
+	}
+	if _, err := getterCompiled.Evaluate(rowGetter{"level": 1}); !errors.As(err, &errs) {
+		t.Errorf("Evaluate() 数值 error = %v, want ValidationErrors", err)
+	}
+	if _, err := getterCompiled.Evaluate(rowGetter{}); !errors.As(err, &notFound) {
+		t.Errorf("Evaluate() 缺少列 error = %v, want *FieldNotFoundError", err)
+	}
+}
+
+// rowGetter 按map中的值实现 FieldGetter 的模型
+type rowGetter map[string]interface{}
+
+// GetField 实现 FieldGetter 接口
+func (r rowGetter) GetField(name string) (interface{}, bool) {
+	value, ok := r[name]
+	return value, ok
+}
+
+// BenchmarkStrictTypeCheckFieldGetter 严格模式下只实现了 FieldGetter 的模型每次评估都校验语法树
+func BenchmarkStrictTypeCheckFieldGetter(b *testing.B) {
+	compiled := MustCompile("(age > 18 AND name LIKE 'a%') OR address.city = 'Beijing'", WithStrictTypeCheck())
+	age := 30
+	profile := &Profile{name: "abc", age: &age, city: "Shanghai"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := compiled.Evaluate(profile); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
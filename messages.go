@@ -0,0 +1,191 @@
+package sqlevaluator
+
+import "fmt"
+
+// Language 错误信息的语言
+type Language string
+
+// 支持的错误信息语言
+const (
+	// Chinese 中文，默认语言
+	Chinese Language = "zh"
+	// English 英文
+	English Language = "en"
+)
+
+// WithLanguage 设置结构化错误和校验错误的信息语言，未设置或不支持的语言使用中文
+//
+// 语言只影响 Error() 返回的文本，错误码和错误中的字段不随语言变化。
+func WithLanguage(lang Language) Option {
+	return func(o *options) {
+		o.language = lang
+	}
+}
+
+// messageID 错误信息在目录中的标识
+type messageID int
+
+const (
+	msgParse messageID = iota
+	msgFieldNotFound
+	msgNotContainer
+	msgTypeMismatch
+	msgTypeMismatchOp
+	msgUnsupported
+	msgLocated
+	msgPositionalParam
+	msgParamUnbound
+	msgParamInvalid
+	msgParamList
+	msgParamExtra
+	msgArity
+	msgArityAtLeast
+	msgArityRange
+	msgArgument
+	msgRegexpTooLong
+	msgRegexpInvalid
+	msgEscape
+	msgXorOperands
+	msgIntegerLiteral
+	msgFloatLiteral
+	msgOutOfRange
+	msgValuerFailed
+	msgValuerSelf
+	msgNilModel
+	msgFuncName
+	msgFuncBuiltin
+	msgFuncNotFunc
+	msgFuncResults
+	msgFuncArgType
+	msgFunctionFailed
+	msgElement
+)
+
+// messageCatalog 各语言的错误信息模板
+var messageCatalog = map[Language]map[messageID]string{
+	Chinese: {
+		msgParse:           "解析SQL失败: %s",
+		msgFieldNotFound:   "字段未找到: %s",
+		msgNotContainer:    "字段 %s 不是结构体或map类型，无法访问 %s",
+		msgTypeMismatch:    "类型不匹配: 无法将%s转换为%s",
+		msgTypeMismatchOp:  "类型不匹配: %s %s %s",
+		msgUnsupported:     "不支持的%s: %s",
+		msgLocated:         "位置%d %q: %s",
+		msgPositionalParam: "第%d个?",
+		msgParamUnbound:    "参数 %s 未绑定",
+		msgParamInvalid:    "参数 %s 无效: %s",
+		msgParamList:       "列表参数 %s 只能用于IN列表",
+		msgParamExtra:      "多余的参数：子句中没有%s",
+		msgArity:           "函数 %s 需要%d个参数，实际为%d个",
+		msgArityAtLeast:    "函数 %s 至少需要%d个参数，实际为%d个",
+		msgArityRange:      "函数 %s 需要%d到%d个参数，实际为%d个",
+		msgArgument:        "函数 %s 的第%d个参数类型错误: %s",
+		msgRegexpTooLong:   "正则表达式长度超过限制: %d > %d",
+		msgRegexpInvalid:   "无效的正则表达式 %q: %v",
+		msgEscape:          "ESCAPE必须是单个字符: %v",
+		msgXorOperands:     "XOR运算需要至少两个操作数",
+		msgIntegerLiteral:  "无法解析整数值: %s",
+		msgFloatLiteral:    "无法解析浮点数值: %s",
+		msgOutOfRange:      "整数超出范围: %s",
+		msgValuerFailed:    "获取 %s 的值失败: %v",
+		msgValuerSelf:      "%s 的 Value 方法返回了自身类型",
+		msgNilModel:        "模型不能为nil",
+		msgFuncName:        "无效的函数名: %q",
+		msgFuncBuiltin:     "函数 %s 与内置函数重名",
+		msgFuncNotFunc:     "函数 %s 必须是非nil的函数，实际为 %s",
+		msgFuncResults:     "函数 %s 必须返回一个值，或者一个值和一个error",
+		msgFuncArgType:     "函数 %s 的第%d个参数类型 %s 不受支持",
+		msgFunctionFailed:  "函数 %s 执行失败: %v",
+		msgElement:         "评估第%d个元素失败: %s",
+	},
+	English: {
+		msgParse:           "failed to parse SQL: %s",
+		msgFieldNotFound:   "field not found: %s",
+		msgNotContainer:    "field %s is not a struct or map, cannot access %s",
+		msgTypeMismatch:    "type mismatch: cannot convert %s to %s",
+		msgTypeMismatchOp:  "type mismatch: %s %s %s",
+		msgUnsupported:     "unsupported %s: %s",
+		msgLocated:         "at position %d %q: %s",
+		msgPositionalParam: "? #%d",
+		msgParamUnbound:    "parameter %s is not bound",
+		msgParamInvalid:    "invalid parameter %s: %s",
+		msgParamList:       "list parameter %s can only be used in an IN list",
+		msgParamExtra:      "extra argument: the clause has no %s",
+		msgArity:           "function %s expects %d arguments, got %d",
+		msgArityAtLeast:    "function %s expects at least %d arguments, got %d",
+		msgArityRange:      "function %s expects %d to %d arguments, got %d",
+		msgArgument:        "argument %[2]d of function %[1]s has the wrong type: %[3]s",
+		msgRegexpTooLong:   "regular expression is too long: %d > %d",
+		msgRegexpInvalid:   "invalid regular expression %q: %v",
+		msgEscape:          "ESCAPE must be a single character: %v",
+		msgXorOperands:     "XOR requires at least two operands",
+		msgIntegerLiteral:  "cannot parse integer value: %s",
+		msgFloatLiteral:    "cannot parse float value: %s",
+		msgOutOfRange:      "integer out of range: %s",
+		msgValuerFailed:    "failed to get value of %s: %v",
+		msgValuerSelf:      "Value method of %s returned its own type",
+		msgNilModel:        "model must not be nil",
+		msgFuncName:        "invalid function name: %q",
+		msgFuncBuiltin:     "function %s conflicts with a built-in function",
+		msgFuncNotFunc:     "function %s must be a non-nil func, got %s",
+		msgFuncResults:     "function %s must return a value, or a value and an error",
+		msgFuncArgType:     "argument %[2]d of function %[1]s has unsupported type %[3]s",
+		msgFunctionFailed:  "function %s failed: %v",
+		msgElement:         "failed to evaluate element %d: %s",
+	},
+}
+
+// nameCatalog 各语言的类型名和语法结构名，英文直接使用原名
+var nameCatalog = map[Language]map[string]string{
+	Chinese: {
+		typeNumber:          "数值",
+		typeString:          "字符串",
+		typeBool:            "布尔值",
+		typeTime:            "时间",
+		typeNull:            "NULL",
+		constructExpression: "表达式类型",
+		constructOperator:   "操作符",
+		constructFunction:   "函数",
+		constructArgument:   "函数参数",
+		constructValue:      "值类型",
+		constructMapKey:     "map键类型",
+		constructStatement:  "语句",
+	},
+}
+
+// catalog 返回语言的信息模板，不支持的语言使用中文
+func (l Language) catalog() map[messageID]string {
+	if messages, ok := messageCatalog[l]; ok {
+		return messages
+	}
+	return messageCatalog[Chinese]
+}
+
+// text 按语言格式化错误信息
+func (l Language) text(id messageID, args ...interface{}) string {
+	return fmt.Sprintf(l.catalog()[id], args...)
+}
+
+// locate 为错误信息加上位置，无法定位时原样返回
+func (l Language) locate(message string, loc Location) string {
+	if loc.Pos < 0 {
+		return message
+	}
+	return l.text(msgLocated, loc.Pos, loc.Fragment, message)
+}
+
+// cause 按语言格式化作为原因的错误，结构化错误使用同一语言
+func (l Language) cause(err error) string {
+	return resolveError(err, nil, l).Error()
+}
+
+// name 返回类型名或语法结构名的翻译，没有翻译时原样返回
+func (l Language) name(name string) string {
+	if _, ok := messageCatalog[l]; !ok {
+		l = Chinese
+	}
+	if translated, ok := nameCatalog[l][name]; ok {
+		return translated
+	}
+	return name
+}
@@ -0,0 +1,205 @@
+package sqlevaluator
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// 评估时的数值统一为以下四种类型：
+//   - int64：所有有符号整数，以及不超过 math.MaxInt64 的无符号整数
+//   - uint64：超过 math.MaxInt64 的无符号整数
+//   - float64：所有浮点数
+//   - *big.Rat：十进制数值（见 Decimal），以及精确十进制模式下的浮点数
+//
+// 整数之间的比较和运算不经过float64，因此大于2^53的整数（如雪花ID）不会丢失精度。
+
+// normalizeUint 将无符号整数转换为评估使用的值，不超过 math.MaxInt64 时为int64
+func normalizeUint(u uint64) interface{} {
+	if u <= math.MaxInt64 {
+		return int64(u)
+	}
+	return u
+}
+
+// normalizeNumber 将任意宽度的Go数值转换为评估使用的值，不是数值时返回false
+func normalizeNumber(value reflect.Value) (interface{}, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return normalizeUint(value.Uint()), true
+	case reflect.Float32:
+		// 按float32的最短十进制表示转换，使 float32(0.1) 等于字面量 0.1
+		f, _ := strconv.ParseFloat(strconv.FormatFloat(value.Float(), 'g', -1, 32), 64)
+		return f, true
+	case reflect.Float64:
+		return value.Float(), true
+	default:
+		return nil, false
+	}
+}
+
+// parseNumber 将字符串解析为数值，整数优先解析为int64，超出范围时依次尝试uint64和float64
+func parseNumber(s string) (interface{}, bool) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, true
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return normalizeUint(u), true
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, true
+	}
+	return nil, false
+}
+
+// isNumber 判断值是否为评估使用的数值类型
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case int64, uint64, float64, *big.Rat:
+		return true
+	default:
+		return false
+	}
+}
+
+// isInteger 判断值是否为评估使用的整数类型
+func isInteger(value interface{}) bool {
+	switch value.(type) {
+	case int64, uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// compareNumbers 精确比较两个数值，返回-1、0或1
+//
+// 整数之间直接比较；整数与浮点数比较时，整数超出float64的精确范围（±2^53）则使用big.Float比较；
+// 任一值为十进制数值时按 *big.Rat 比较。NaN小于其他任何数值。
+func compareNumbers(a, b interface{}) int {
+	if isDecimal(a) || isDecimal(b) {
+		return compareDecimal(a, b)
+	}
+	switch x := a.(type) {
+	case int64:
+		switch y := b.(type) {
+		case int64:
+			return compareInt64(x, y)
+		case uint64:
+			if x < 0 {
+				return -1
+			}
+			return compareUint64(uint64(x), y)
+		case float64:
+			if x >= -1<<53 && x <= 1<<53 {
+				return compareFloat64(float64(x), y)
+			}
+			return compareBigFloat(new(big.Float).SetInt64(x), y)
+		}
+	case uint64:
+		switch y := b.(type) {
+		case int64:
+			return -compareNumbers(y, x)
+		case uint64:
+			return compareUint64(x, y)
+		case float64:
+			if x <= 1<<53 {
+				return compareFloat64(float64(x), y)
+			}
+			return compareBigFloat(new(big.Float).SetUint64(x), y)
+		}
+	case float64:
+		switch y := b.(type) {
+		case int64, uint64:
+			return -compareNumbers(y, x)
+		case float64:
+			return compareFloat64(x, y)
+		}
+	}
+	panic(fmt.Sprintf("compareNumbers: 不是数值: %T 和 %T", a, b))
+}
+
+// compareInt64 比较两个int64
+func compareInt64(x, y int64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareUint64 比较两个uint64
+func compareUint64(x, y uint64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareFloat64 比较两个float64，NaN小于其他任何数值
+func compareFloat64(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	case x == y:
+		return 0
+	}
+	switch {
+	case math.IsNaN(x) && math.IsNaN(y):
+		return 0
+	case math.IsNaN(x):
+		return -1
+	default:
+		return 1
+	}
+}
+
+// compareBigFloat 比较超出float64精确范围的整数与浮点数
+func compareBigFloat(x *big.Float, y float64) int {
+	switch {
+	case math.IsNaN(y):
+		return 1
+	case math.IsInf(y, 1):
+		return -1
+	case math.IsInf(y, -1):
+		return 1
+	default:
+		return x.Cmp(big.NewFloat(y))
+	}
+}
+
+// toBigInt 将整数转换为big.Int
+func toBigInt(value interface{}) *big.Int {
+	switch v := value.(type) {
+	case int64:
+		return big.NewInt(v)
+	case uint64:
+		return new(big.Int).SetUint64(v)
+	default:
+		panic(fmt.Sprintf("toBigInt: 不是整数: %T", value))
+	}
+}
+
+// fromBigInt 将big.Int转换为评估使用的整数，超出64位整数范围时返回错误
+func fromBigInt(b *big.Int) (interface{}, error) {
+	if b.IsInt64() {
+		return b.Int64(), nil
+	}
+	if b.IsUint64() {
+		return b.Uint64(), nil
+	}
+	return nil, newOutOfRange(b.String())
+}
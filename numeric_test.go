@@ -0,0 +1,248 @@
+package sqlevaluator
+
+import (
+	"math"
+	"testing"
+)
+
+// Metric 数值宽度测试使用的模型
+type Metric struct {
+	ID       int64    `json:"id"`
+	ParentID *int64   `json:"parent_id"`
+	Shard    int32    `json:"shard"`
+	Priority int8     `json:"priority"`
+	Flags    uint8    `json:"flags"`
+	Port     *uint16  `json:"port"`
+	Counter  uint64   `json:"counter"`
+	Ratio    float32  `json:"ratio"`
+	Weight   *float32 `json:"weight"`
+	Level    Level    `json:"level"`
+}
+
+// Level 底层类型为字符串的自定义类型
+type Level string
+
+func TestSQLEvaluatorNumericWidths(t *testing.T) {
+	parentID := int64(1234567890123456789)
+	port := uint16(8080)
+	weight := float32(0.3)
+	metric := &Metric{
+		// 雪花ID，大于2^53
+		ID:       1234567890123456790,
+		ParentID: &parentID,
+		Shard:    -7,
+		Priority: 3,
+		Flags:    0b1010,
+		Port:     &port,
+		Counter:  math.MaxUint64,
+		Ratio:    0.1,
+		Weight:   &weight,
+		Level:    "gold",
+	}
+
+	tests := []struct {
+		name        string
+		whereClause string
+		args        []interface{}
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "int64雪花ID精确相等",
+			whereClause: "id = 1234567890123456790",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "int64雪花ID相差1",
+			whereClause: "id = 1234567890123456789",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "int64指针字段",
+			whereClause: "parent_id < id AND parent_id = id - 1",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "int64雪花ID在IN列表中",
+			whereClause: "id IN (1234567890123456789, 1234567890123456790)",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "int64雪花ID作为参数",
+			whereClause: "id = ? AND parent_id IN ?",
+			args:        []interface{}{int64(1234567890123456790), []int64{1234567890123456789}},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "int64与浮点数比较不经过float64",
+			whereClause: "id > 1234567890123456789.0",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "int32和int8",
+			whereClause: "shard < 0 AND priority BETWEEN 1 AND 5",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "uint8位运算",
+			whereClause: "flags & 2 = 2 AND flags & 1 = 0",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "uint16指针字段",
+			whereClause: "port = 8080",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "uint64最大值",
+			whereClause: "counter = 18446744073709551615 AND counter > 9223372036854775807",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "uint64与负数比较",
+			whereClause: "counter > -1",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "uint64参数",
+			whereClause: "counter = ?",
+			args:        []interface{}{uint64(math.MaxUint64)},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "float32按十进制表示比较",
+			whereClause: "ratio = 0.1 AND weight = 0.3",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "整数与字符串比较",
+			whereClause: "id = '1234567890123456790'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "底层类型为字符串的自定义类型",
+			whereClause: "level = 'gold' AND level LIKE 'g%'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "int64运算不溢出时保持精度",
+			whereClause: "id + 1 = 1234567890123456791",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "最小int64字面量",
+			whereClause: "-9223372036854775808 < shard",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "uint64运算",
+			whereClause: "counter - 1 = 18446744073709551614 AND counter DIV 2 = 9223372036854775807",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "超出uint64范围的整数字面量",
+			whereClause: "counter < 99999999999999999999 AND id < 99999999999999999999",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "超出uint64范围的整数字面量参与运算",
+			whereClause: "99999999999999999999 - counter > 0",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "整数运算溢出",
+			whereClause: "counter + 1 > 0",
+			want:        false,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(metric)
+			got, err := evaluator.EvaluateWhere(tt.whereClause, tt.args...)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareNumbers(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b interface{}
+		want int
+	}{
+		{
+			name: "int64",
+			a:    int64(-1),
+			b:    int64(1),
+			want: -1,
+		},
+		{
+			name: "int64与uint64",
+			a:    int64(math.MaxInt64),
+			b:    uint64(math.MaxInt64 + 1),
+			want: -1,
+		},
+		{
+			name: "负数与uint64",
+			a:    uint64(math.MaxUint64),
+			b:    int64(-1),
+			want: 1,
+		},
+		{
+			name: "超过2^53的int64与float64",
+			a:    int64(1<<53 + 1),
+			b:    float64(1 << 53),
+			want: 1,
+		},
+		{
+			name: "float64与int64相等",
+			a:    float64(3),
+			b:    int64(3),
+			want: 0,
+		},
+		{
+			name: "uint64与正无穷",
+			a:    uint64(math.MaxUint64),
+			b:    math.Inf(1),
+			want: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compareNumbers(tt.a, tt.b); got != tt.want {
+				t.Errorf("compareNumbers(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
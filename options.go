@@ -0,0 +1,107 @@
+package sqlevaluator
+
+import "time"
+
+// Option 评估器配置项
+type Option func(*options)
+
+// options 评估器配置
+type options struct {
+	// location 解析不带时区的时间字面量时使用的时区，为nil时使用被比较字段的时区
+	location *time.Location
+	// threeValuedLogic 是否使用SQL三值逻辑处理NULL
+	threeValuedLogic bool
+	// caseInsensitiveLike LIKE和REGEXP是否不区分大小写
+	caseInsensitiveLike bool
+	// regexpLimit 正则表达式的最大长度，0表示使用默认值，负数表示不限制
+	regexpLimit int
+	// exactDecimal 是否按十进制精确计算浮点数
+	exactDecimal bool
+	// language 错误信息的语言，为空时使用中文
+	language Language
+	// strictTypeCheck 是否在评估前按模型类型校验整个子句
+	strictTypeCheck bool
+}
+
+// newOptions 根据配置项生成评估器配置
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithLocation 设置解析不带时区的时间字面量（如 '2024-03-21 10:00:00'）时使用的时区
+//
+// 未设置时，字面量按与之比较的时间字段自身的时区解析；带时区的RFC3339字面量不受影响。
+func WithLocation(loc *time.Location) Option {
+	return func(o *options) {
+		o.location = loc
+	}
+}
+
+// WithCaseInsensitiveLike 启用不区分大小写的LIKE和REGEXP，LIKE与PostgreSQL的ILIKE一致
+//
+// 默认情况下LIKE、NOT LIKE、REGEXP、NOT REGEXP和RLIKE都区分大小写，与Go的字符串比较一致；
+// MySQL的默认排序规则不区分大小写，需要与MySQL的结果一致时启用该选项。
+// 启用后正则表达式相当于带有 (?i) 标志，模式中的 (?-i) 仍可以恢复区分大小写。
+func WithCaseInsensitiveLike() Option {
+	return func(o *options) {
+		o.caseInsensitiveLike = true
+	}
+}
+
+// WithMaxRegexpLength 设置REGEXP模式的最大长度（字符数），n小于等于0表示不限制
+//
+// 未设置时最大长度为1024。字面量模式在编译时检查，来自字段或参数的模式在评估时检查。
+func WithMaxRegexpLength(n int) Option {
+	return func(o *options) {
+		if n <= 0 {
+			n = -1
+		}
+		o.regexpLimit = n
+	}
+}
+
+// maxRegexpLength 返回正则表达式的最大长度，小于等于0表示不限制
+func (o options) maxRegexpLength() int {
+	if o.regexpLimit == 0 {
+		return defaultMaxRegexpLength
+	}
+	return o.regexpLimit
+}
+
+// WithExactDecimal 启用精确十进制模式，适用于金额等不允许浮点误差的场景
+//
+// 启用后小数字面量（如 0.1）、浮点数字段和参数、非整数的数值字符串都按十进制精确表示，
+// 算术运算和比较不再经过float64，因此 0.1 + 0.2 = 0.3 为true，除法的结果也是精确的。
+// 实现了 Decimal 接口的值不论是否启用都按十进制精确比较和运算。
+func WithExactDecimal() Option {
+	return func(o *options) {
+		o.exactDecimal = true
+	}
+}
+
+// WithThreeValuedLogic 启用SQL三值逻辑
+//
+// 默认情况下任何涉及NULL的比较都返回false，因此 NOT (x = NULL) 为true。启用后与NULL比较得到UNKNOWN，
+// UNKNOWN按MySQL/PostgreSQL的规则参与NOT、AND、OR、XOR和IN运算，只在最终结果中视为false，
+// 从而与数据库返回的结果集保持一致。
+func WithThreeValuedLogic() Option {
+	return func(o *options) {
+		o.threeValuedLogic = true
+	}
+}
+
+// WithStrictTypeCheck 启用严格模式，评估前按模型类型校验整个子句，与 Validate 的检查相同
+//
+// AND和OR按短路求值，左侧已经决定结果时不评估右侧，默认情况下右侧的字段拼写错误、类型不匹配等错误因此不会返回，
+// 同一条件对不同记录可能有的报错、有的不报错。启用后任何分支存在错误时都返回 ValidationErrors，
+// 不论该分支是否会被评估；编译结果对每种模型类型只校验一次，只实现了 FieldGetter 的模型每次评估都校验编译时解析的语法树。
+// 校验比评估更严格，例如对数值字段使用LIKE在评估时按字符串匹配，在严格模式下返回错误。
+func WithStrictTypeCheck() Option {
+	return func(o *options) {
+		o.strictTypeCheck = true
+	}
+}
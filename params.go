@@ -0,0 +1,129 @@
+package sqlevaluator
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// positionalParams 将按顺序传入的参数转换为参数表，sqlparser 将第N个 ? 命名为 :vN
+func positionalParams(args []interface{}) (map[string]interface{}, error) {
+	params := make(map[string]interface{}, len(args))
+	for i, arg := range args {
+		value, err := normalizeParam(arg)
+		if err != nil {
+			return nil, newParamError("v"+strconv.Itoa(i+1), paramInvalid, err)
+		}
+		params["v"+strconv.Itoa(i+1)] = value
+	}
+	return params, nil
+}
+
+// positionalIndex 返回参数名 vN 对应第几个 ?，不是位置参数时返回false
+func positionalIndex(name string) (int, bool) {
+	if !strings.HasPrefix(name, "v") {
+		return 0, false
+	}
+	n, err := strconv.Atoi(name[1:])
+	return n, err == nil && n > 0
+}
+
+// countPlaceholders 返回参数名中 ? 占位符的个数
+func countPlaceholders(names []string) int {
+	count := 0
+	for _, name := range names {
+		if _, ok := positionalIndex(name); ok {
+			count++
+		}
+	}
+	return count
+}
+
+// namedParams 规范化命名参数，参数名可以带或不带前导冒号
+func namedParams(named map[string]interface{}) (map[string]interface{}, error) {
+	params := make(map[string]interface{}, len(named))
+	for name, arg := range named {
+		name = strings.TrimPrefix(name, ":")
+		value, err := normalizeParam(arg)
+		if err != nil {
+			return nil, newParamError(name, paramInvalid, err)
+		}
+		params[name] = value
+	}
+	return params, nil
+}
+
+// normalizeParam 将参数转换为评估使用的值，切片（[]byte 除外）转换为 []interface{} 以便在IN列表中展开
+func normalizeParam(arg interface{}) (interface{}, error) {
+	if arg == nil {
+		return nil, nil
+	}
+
+	value := reflect.ValueOf(arg)
+	if b, ok := arg.([]byte); ok {
+		return string(b), nil
+	}
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		values := make([]interface{}, value.Len())
+		for i := range values {
+			item, err := normalizeParam(value.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			values[i] = item
+		}
+		return values, nil
+	}
+
+	return normalizeValue(value)
+}
+
+// collectParams 返回语法树中引用的参数名（不含前导冒号），按首次出现的顺序排列
+func collectParams(expr sqlparser.Expr) []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch n := node.(type) {
+		case *sqlparser.SQLVal:
+			if n.Type == sqlparser.ValArg {
+				add(strings.TrimPrefix(string(n.Val), ":"))
+			}
+		case sqlparser.ListArg:
+			add(strings.TrimPrefix(string(n), "::"))
+		}
+		return true, nil
+	}, expr)
+
+	return names
+}
+
+// checkParams 检查子句引用的参数是否都已绑定，args 为按位置传入的参数个数，不能多于子句中的 ?
+func (c *CompiledWhere) checkParams(params map[string]interface{}, args int) error {
+	if args > c.placeholders {
+		return newParamError("v"+strconv.Itoa(c.placeholders+1), paramExtra, nil)
+	}
+	for _, name := range c.params {
+		if _, ok := params[name]; !ok {
+			return newParamError(name, paramUnbound, nil)
+		}
+	}
+	return nil
+}
+
+// getParam 获取已绑定的参数值
+func (e *SQLEvaluator) getParam(name string) (interface{}, error) {
+	value, ok := e.params[name]
+	if !ok {
+		return nil, newParamError(name, paramUnbound, nil)
+	}
+	return value, nil
+}
@@ -0,0 +1,238 @@
+package sqlevaluator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSQLEvaluatorParams(t *testing.T) {
+	user := &User{
+		ID:       intPtr(3),
+		Name:     strPtr("张三"),
+		Age:      intPtr(25),
+		Salary:   float64Ptr(5000.50),
+		IsActive: boolPtr(true),
+	}
+
+	tests := []struct {
+		name        string
+		whereClause string
+		args        []interface{}
+		named       map[string]interface{}
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "位置参数",
+			whereClause: "age > ? AND name = ?",
+			args:        []interface{}{20, "张三"},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "位置参数不匹配",
+			whereClause: "age > ?",
+			args:        []interface{}{30},
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "参数不按字符串比较",
+			whereClause: "age > ?",
+			args:        []interface{}{"9"},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "指针参数",
+			whereClause: "salary >= ?",
+			args:        []interface{}{float64Ptr(5000)},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "NULL参数",
+			whereClause: "NOT (age = ?)",
+			args:        []interface{}{nil},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "切片参数展开为IN列表",
+			whereClause: "id IN ?",
+			args:        []interface{}{[]int{1, 2, 3}},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "括号中的切片参数",
+			whereClause: "id NOT IN (?, 10)",
+			args:        []interface{}{[]int{4, 5}},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "BETWEEN参数",
+			whereClause: "age BETWEEN ? AND ? AND is_active = ?",
+			args:        []interface{}{18, 30, true},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "命名参数",
+			whereClause: "age >= :min_age AND name LIKE :pattern",
+			named:       map[string]interface{}{"min_age": 18, "pattern": "张%"},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "带冒号的命名参数",
+			whereClause: "age >= :min_age",
+			named:       map[string]interface{}{":min_age": 30},
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "命名切片参数",
+			whereClause: "name IN :names",
+			named:       map[string]interface{}{"names": []string{"李四", "张三"}},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "双冒号列表参数",
+			whereClause: "id IN ::ids",
+			named:       map[string]interface{}{"ids": []int{7, 8}},
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "重复引用的命名参数",
+			whereClause: "age > :n OR salary > :n",
+			named:       map[string]interface{}{"n": 30},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "缺少位置参数",
+			whereClause: "age > ? AND name = ?",
+			args:        []interface{}{20},
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "缺少命名参数",
+			whereClause: "age > :min_age OR name = :name",
+			named:       map[string]interface{}{"name": "张三"},
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "位置参数多于占位符",
+			whereClause: "age = ?",
+			args:        []interface{}{25, 40},
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "没有占位符时传入位置参数",
+			whereClause: "age = 25",
+			args:        []interface{}{25},
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "命名参数可以多于占位符",
+			whereClause: "age = :age",
+			named:       map[string]interface{}{"age": 25, "name": "张三"},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "切片参数不能用于比较",
+			whereClause: "id = ?",
+			args:        []interface{}{[]int{1, 2}},
+			want:        false,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(user)
+
+			var got bool
+			var err error
+			if tt.named != nil {
+				got, err = evaluator.EvaluateWhereNamed(tt.whereClause, tt.named)
+			} else {
+				got, err = evaluator.EvaluateWhere(tt.whereClause, tt.args...)
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCompiledWhereParams 验证同一规则模板可以绑定不同的参数重复评估
+func TestCompiledWhereParams(t *testing.T) {
+	rule := MustCompile("created_at >= :since AND id IN :ids")
+	event := &Event{ID: 2, CreatedAt: time.Date(2024, 3, 21, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		want   bool
+	}{
+		{
+			name:   "租户A",
+			params: map[string]interface{}{"since": "2024-03-01", "ids": []int{1, 2}},
+			want:   true,
+		},
+		{
+			name:   "租户B",
+			params: map[string]interface{}{"since": time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), "ids": []int{1, 2}},
+			want:   false,
+		},
+		{
+			name:   "租户C",
+			params: map[string]interface{}{"since": "2024-03-01", "ids": []int{3}},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rule.EvaluateNamed(event, tt.params)
+			if err != nil {
+				t.Fatalf("EvaluateNamed() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("EvaluateNamed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	positional := MustCompile("id = ? OR id = ?")
+	got, err := positional.Evaluate(event, 1, 2)
+	if err != nil || !got {
+		t.Errorf("Evaluate() = %v, %v, want true, nil", got, err)
+	}
+	// 多余的位置参数返回错误，不会被忽略
+	if _, err := positional.Evaluate(event, 1, 2, 3); err == nil {
+		t.Error("Evaluate() 多余的位置参数 error = nil")
+	}
+	if _, err := AnyCompiled([]Event{*event}, positional, 1, 2, 3); err == nil {
+		t.Error("AnyCompiled() 多余的位置参数 error = nil")
+	}
+	if _, err := NewSQLEvaluator(event).EvaluateWhereExplain("", 1); err == nil {
+		t.Error("EvaluateWhereExplain() 多余的位置参数 error = nil")
+	}
+}
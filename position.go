@@ -0,0 +1,389 @@
+package sqlevaluator
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// span 语法树节点在子句中对应的词法单元范围，first 和 last 为词法单元下标，无法定位时为-1
+type span struct {
+	first, last int
+}
+
+// noSpan 无法定位的范围
+var noSpan = span{first: -1, last: -1}
+
+// valid 判断范围是否有效
+func (s span) valid() bool {
+	return s.first >= 0 && s.last >= s.first
+}
+
+// union 返回同时覆盖两个范围的最小范围
+func (s span) union(other span) span {
+	if !s.valid() {
+		return other
+	}
+	if !other.valid() {
+		return s
+	}
+	if other.first < s.first {
+		s.first = other.first
+	}
+	if other.last > s.last {
+		s.last = other.last
+	}
+	return s
+}
+
+// tokenClass 按出现顺序与语法树叶子节点对应的词法单元类别
+type tokenClass int
+
+const (
+	classString tokenClass = iota
+	classNumber
+	classValueArg
+	classListArg
+	classBool
+	classNull
+	classCount
+)
+
+// clauseLocator 记录语法树节点在原始WHERE子句中的位置
+//
+// sqlparser的语法树不保存位置，且子句在解析前可能被改写，因此按词法单元的出现顺序定位：
+// 语法树按源码顺序遍历时，第n个字符串字面量对应子句中的第n个字符串，第n次出现的列 a.b 对应子句中第n处 a.b，
+// 复合表达式的范围由子节点的范围和两侧的括号、关键字确定。
+type clauseLocator struct {
+	clause string
+	tokens []clauseToken
+	// spans 指针类型节点的范围，BoolVal、ValTuple 等值类型节点不能作为键
+	spans map[sqlparser.SQLNode]span
+
+	// classes 每一类字面量在子句中的词法单元下标，next 为下一个待对应的序号
+	classes [classCount][]int
+	next    [classCount]int
+	// columns 列的起始词法单元下标，键为小写的列名路径
+	columns    map[string][]int
+	nextColumn map[string]int
+	// functions 函数名的词法单元下标，键为小写的函数名
+	functions    map[string][]int
+	nextFunction map[string]int
+}
+
+// locateNodes 计算语法树中各节点在原始子句中的位置，子句存在词法错误时所有节点都无法定位
+func locateNodes(clause string, expr sqlparser.Expr) *clauseLocator {
+	l := &clauseLocator{
+		clause:       clause,
+		spans:        make(map[sqlparser.SQLNode]span),
+		columns:      make(map[string][]int),
+		nextColumn:   make(map[string]int),
+		functions:    make(map[string][]int),
+		nextFunction: make(map[string]int),
+	}
+	if tokens, ok := scanTokens(clause); ok {
+		l.tokens = tokens
+	}
+	l.indexTokens()
+	if expr != nil {
+		l.spanOf(expr)
+	}
+	return l
+}
+
+// indexTokens 按类别记录词法单元的下标
+func (l *clauseLocator) indexTokens() {
+	for i, token := range l.tokens {
+		switch token.typ {
+		case sqlparser.STRING:
+			l.classes[classString] = append(l.classes[classString], i)
+		case sqlparser.INTEGRAL, sqlparser.FLOAT, sqlparser.HEXNUM, sqlparser.HEX, sqlparser.BIT_LITERAL:
+			l.classes[classNumber] = append(l.classes[classNumber], i)
+		case sqlparser.VALUE_ARG:
+			l.classes[classValueArg] = append(l.classes[classValueArg], i)
+		case sqlparser.LIST_ARG:
+			l.classes[classListArg] = append(l.classes[classListArg], i)
+		case sqlparser.TRUE, sqlparser.FALSE, sqlparser.NULL:
+			// IS NULL、IS NOT TRUE 中的关键字属于IS操作符，不是字面量
+			if l.isOperatorKeyword(i) {
+				continue
+			}
+			if token.typ == sqlparser.NULL {
+				l.classes[classNull] = append(l.classes[classNull], i)
+			} else {
+				l.classes[classBool] = append(l.classes[classBool], i)
+			}
+		}
+
+		if !isIdentifierText(token.text(l.clause)) || (i > 0 && l.tokens[i-1].typ == '.') {
+			continue
+		}
+		if i+1 < len(l.tokens) && l.tokens[i+1].typ == '(' {
+			name := strings.ToLower(token.text(l.clause))
+			l.functions[name] = append(l.functions[name], i)
+			continue
+		}
+		if path, ok := l.columnPathAt(i); ok {
+			l.columns[path] = append(l.columns[path], i)
+		}
+	}
+}
+
+// isOperatorKeyword 判断第i个词法单元是否为 IS [NOT] 之后的 NULL、TRUE 或 FALSE
+func (l *clauseLocator) isOperatorKeyword(i int) bool {
+	if i > 0 && l.tokens[i-1].typ == sqlparser.IS {
+		return true
+	}
+	return i > 1 && l.tokens[i-1].typ == sqlparser.NOT && l.tokens[i-2].typ == sqlparser.IS
+}
+
+// columnPathAt 返回从第i个词法单元开始的列名路径，如 address.city，后面紧跟 '(' 的是函数调用
+func (l *clauseLocator) columnPathAt(i int) (string, bool) {
+	parts := []string{unquoteIdentifier(l.tokens[i].text(l.clause))}
+	j := i
+	for j+2 < len(l.tokens) && l.tokens[j+1].typ == '.' && isIdentifierText(l.tokens[j+2].text(l.clause)) {
+		j += 2
+		parts = append(parts, unquoteIdentifier(l.tokens[j].text(l.clause)))
+	}
+	if j+1 < len(l.tokens) && l.tokens[j+1].typ == '(' {
+		return "", false
+	}
+	return strings.ToLower(strings.Join(parts, ".")), true
+}
+
+// isIdentifierText 判断文本是否为标识符或关键字
+func isIdentifierText(text string) bool {
+	if strings.HasPrefix(text, "`") {
+		return true
+	}
+	if text == "" {
+		return false
+	}
+	for _, ch := range text {
+		if !(ch == '_' || ch == '$' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' || ch >= 0x80) {
+			return false
+		}
+	}
+	return !(text[0] >= '0' && text[0] <= '9')
+}
+
+// unquoteIdentifier 去掉标识符两侧的反引号
+func unquoteIdentifier(text string) string {
+	if len(text) >= 2 && text[0] == '`' && text[len(text)-1] == '`' {
+		return strings.ReplaceAll(text[1:len(text)-1], "``", "`")
+	}
+	return text
+}
+
+// take 返回某类字面量中下一个未对应的词法单元
+func (l *clauseLocator) take(class tokenClass) span {
+	n := l.next[class]
+	l.next[class]++
+	if n < len(l.classes[class]) {
+		i := l.classes[class][n]
+		return span{first: i, last: i}
+	}
+	return noSpan
+}
+
+// spanOf 按源码顺序计算节点及其子节点的范围
+func (l *clauseLocator) spanOf(node sqlparser.SQLNode) span {
+	var s span
+	switch n := node.(type) {
+	case *sqlparser.ColName:
+		s = l.columnSpan(n)
+	case *sqlparser.SQLVal:
+		s = l.take(sqlValClass(n))
+	case sqlparser.BoolVal:
+		s = l.take(classBool)
+	case *sqlparser.NullVal:
+		s = l.take(classNull)
+	case sqlparser.ListArg:
+		s = l.take(classListArg)
+	case *sqlparser.FuncExpr:
+		s = l.funcSpan(n)
+	default:
+		s = l.childrenSpan(node)
+		s = l.extend(node, s)
+	}
+
+	if reflect.ValueOf(node).Kind() == reflect.Ptr {
+		l.spans[node] = s
+	}
+	return s
+}
+
+// childrenSpan 返回所有直接子节点范围的并集
+func (l *clauseLocator) childrenSpan(node sqlparser.SQLNode) span {
+	s := noSpan
+	// Walk 先访问节点本身，再访问直接子节点
+	root := true
+	_ = sqlparser.Walk(func(child sqlparser.SQLNode) (bool, error) {
+		if root {
+			root = false
+			return true, nil
+		}
+		s = s.union(l.spanOf(child))
+		return false, nil
+	}, node)
+	return s
+}
+
+// sqlValClass 返回字面量对应的词法单元类别
+func sqlValClass(val *sqlparser.SQLVal) tokenClass {
+	switch val.Type {
+	case sqlparser.StrVal:
+		return classString
+	case sqlparser.ValArg:
+		return classValueArg
+	default:
+		return classNumber
+	}
+}
+
+// columnSpan 返回列的范围
+func (l *clauseLocator) columnSpan(col *sqlparser.ColName) span {
+	path := strings.ToLower(columnPath(col))
+	n := l.nextColumn[path]
+	l.nextColumn[path]++
+	if n >= len(l.columns[path]) {
+		return noSpan
+	}
+	first := l.columns[path][n]
+	return span{first: first, last: first + 2*strings.Count(path, ".")}
+}
+
+// funcSpan 返回函数调用的范围，从函数名到匹配的右括号
+//
+// 改写XOR得到的函数在子句中没有函数名，范围由操作数确定。
+func (l *clauseLocator) funcSpan(fn *sqlparser.FuncExpr) span {
+	args := l.childrenSpan(fn)
+	if fn.Name.EqualString(xorFuncName) {
+		return args
+	}
+
+	name := fn.Name.Lowered()
+	candidates := l.functions[name]
+	if name == substringFuncName {
+		// SUBSTR(...) 改写后与 SUBSTRING(...) 同名
+		candidates = mergeIndexes(candidates, l.functions["substr"])
+	}
+	n := l.nextFunction[name]
+	l.nextFunction[name]++
+	if n >= len(candidates) {
+		return args
+	}
+	first := candidates[n]
+	return span{first: first, last: l.closingParen(first + 1)}
+}
+
+// mergeIndexes 合并两个递增的下标序列
+func mergeIndexes(a, b []int) []int {
+	merged := make([]int, 0, len(a)+len(b))
+	for len(a) > 0 || len(b) > 0 {
+		if len(b) == 0 || (len(a) > 0 && a[0] < b[0]) {
+			merged, a = append(merged, a[0]), a[1:]
+		} else {
+			merged, b = append(merged, b[0]), b[1:]
+		}
+	}
+	return merged
+}
+
+// closingParen 返回与第open个词法单元 '(' 匹配的 ')' 的下标，未找到时返回最后一个词法单元
+func (l *clauseLocator) closingParen(open int) int {
+	depth := 0
+	for i := open; i < len(l.tokens); i++ {
+		switch l.tokens[i].typ {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(l.tokens) - 1
+}
+
+// extend 将复合表达式的范围扩展到两侧的括号和关键字
+func (l *clauseLocator) extend(node sqlparser.SQLNode, s span) span {
+	if !s.valid() {
+		return s
+	}
+	switch node.(type) {
+	case *sqlparser.ParenExpr, sqlparser.ValTuple:
+		if l.tokenIs(s.first-1, '(') && l.tokenIs(s.last+1, ')') {
+			s.first--
+			s.last++
+		}
+	case *sqlparser.NotExpr:
+		if l.tokenIs(s.first-1, sqlparser.NOT) || l.tokenIs(s.first-1, '!') {
+			s.first--
+		}
+	case *sqlparser.UnaryExpr:
+		if l.tokenIs(s.first-1, '-') || l.tokenIs(s.first-1, '+') || l.tokenIs(s.first-1, '~') || l.tokenIs(s.first-1, '!') {
+			s.first--
+		}
+	case *sqlparser.IsExpr:
+		for i := s.last + 1; i < len(l.tokens) && i <= s.last+3; i++ {
+			typ := l.tokens[i].typ
+			if typ == sqlparser.NULL || typ == sqlparser.TRUE || typ == sqlparser.FALSE {
+				s.last = i
+				break
+			}
+		}
+	case *sqlparser.CaseExpr:
+		for i := s.first - 1; i >= 0; i-- {
+			if l.tokens[i].typ == sqlparser.CASE {
+				s.first = i
+				break
+			}
+		}
+		for i := s.last + 1; i < len(l.tokens); i++ {
+			if l.tokens[i].typ == sqlparser.END {
+				s.last = i
+				break
+			}
+		}
+	case *sqlparser.SubstrExpr:
+		for i := s.first - 1; i >= 0; i-- {
+			if typ := l.tokens[i].typ; typ == sqlparser.SUBSTRING || typ == sqlparser.SUBSTR {
+				s.first = i
+				break
+			}
+		}
+		if l.tokenIs(s.last+1, ')') {
+			s.last++
+		}
+	}
+	return s
+}
+
+// tokenIs 判断第i个词法单元是否为指定类型
+func (l *clauseLocator) tokenIs(i, typ int) bool {
+	return i >= 0 && i < len(l.tokens) && l.tokens[i].typ == typ
+}
+
+// lookup 返回节点的范围
+func (l *clauseLocator) lookup(node sqlparser.SQLNode) span {
+	if node == nil || reflect.ValueOf(node).Kind() != reflect.Ptr {
+		return noSpan
+	}
+	if s, ok := l.spans[node]; ok {
+		return s
+	}
+	return noSpan
+}
+
+// position 返回节点在子句中的字节偏移和原始文本，无法定位时偏移为-1
+func (l *clauseLocator) position(node sqlparser.SQLNode) (int, string) {
+	s := l.lookup(node)
+	if !s.valid() || s.last >= len(l.tokens) {
+		return -1, ""
+	}
+	start, end := l.tokens[s.first].start, l.tokens[s.last].end
+	return start, l.clause[start:end]
+}
@@ -0,0 +1,84 @@
+package sqlevaluator
+
+import (
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// defaultMaxRegexpLength 正则表达式的默认最大长度（字符数）
+const defaultMaxRegexpLength = 1024
+
+// isRegexpOperator 判断是否为REGEXP或NOT REGEXP操作符，RLIKE解析后与REGEXP相同
+func isRegexpOperator(operator string) bool {
+	return operator == sqlparser.RegexpStr || operator == sqlparser.NotRegexpStr
+}
+
+// evaluateRegexp 评估REGEXP操作符，左侧的值中任意位置匹配正则表达式即为真
+//
+// 模式为字面量时使用编译时预编译的正则表达式，否则每次评估时编译。
+func (e *SQLEvaluator) evaluateRegexp(expr *sqlparser.ComparisonExpr, left, right interface{}) (bool, error) {
+	leftStr, err := toString(left)
+	if err != nil {
+		return false, withNode(newConversionError(left, typeString, expr.Operator), expr.Left)
+	}
+
+	re, ok := e.compiled.regexps[expr]
+	if !ok {
+		rightStr, err := toString(right)
+		if err != nil {
+			return false, withNode(newConversionError(right, typeString, expr.Operator), expr.Right)
+		}
+		re, err = compileRegexp(rightStr, e.options)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return re.MatchString(leftStr), nil
+}
+
+// compileRegexp 编译正则表达式，超过长度限制时返回错误
+//
+// 启用 WithCaseInsensitiveLike 时与LIKE一样不区分大小写，相当于在模式前加上 (?i) 标志。
+func compileRegexp(pattern string, opts options) (*regexp.Regexp, error) {
+	if limit := opts.maxRegexpLength(); limit > 0 && utf8.RuneCountInString(pattern) > limit {
+		return nil, newInvalidArgument(reasonRegexp, pattern, nil, msgRegexpTooLong, utf8.RuneCountInString(pattern), limit)
+	}
+
+	expr := pattern
+	if opts.caseInsensitiveLike {
+		expr = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, newInvalidArgument(reasonRegexp, pattern, err, msgRegexpInvalid, pattern, err)
+	}
+	return re, nil
+}
+
+// compileRegexps 预编译子句中模式为字面量的REGEXP表达式，无效的正则表达式在编译时报告
+func compileRegexps(expr sqlparser.Expr, opts options) (map[*sqlparser.ComparisonExpr]*regexp.Regexp, error) {
+	var regexps map[*sqlparser.ComparisonExpr]*regexp.Regexp
+	err := sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		comparison, ok := node.(*sqlparser.ComparisonExpr)
+		if !ok || !isRegexpOperator(comparison.Operator) || !isStringLiteral(comparison.Right) {
+			return true, nil
+		}
+
+		re, err := compileRegexp(string(comparison.Right.(*sqlparser.SQLVal).Val), opts)
+		if err != nil {
+			return false, withNode(err, comparison)
+		}
+		if regexps == nil {
+			regexps = make(map[*sqlparser.ComparisonExpr]*regexp.Regexp)
+		}
+		regexps[comparison] = re
+		return true, nil
+	}, expr)
+	if err != nil {
+		return nil, err
+	}
+	return regexps, nil
+}
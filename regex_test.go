@@ -0,0 +1,197 @@
+package sqlevaluator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSQLEvaluatorRegexp(t *testing.T) {
+	logEntry := map[string]interface{}{
+		"path":    "/api/v2/orders/123",
+		"level":   "ERROR",
+		"status":  503,
+		"pattern": "^/api/v[0-9]+/",
+		"trace":   nil,
+	}
+
+	tests := []struct {
+		name        string
+		whereClause string
+		args        []interface{}
+		opts        []Option
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "REGEXP匹配",
+			whereClause: "path REGEXP '^/api/v[0-9]+/orders'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "REGEXP在任意位置匹配",
+			whereClause: "path REGEXP 'orders/[0-9]+$'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "NOT REGEXP",
+			whereClause: "level NOT REGEXP '^(INFO|DEBUG)$'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "RLIKE",
+			whereClause: "path RLIKE 'v2' AND path NOT RLIKE 'v3'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "REGEXP区分大小写",
+			whereClause: "level REGEXP 'error'",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "正则表达式中的不区分大小写标志",
+			whereClause: "level REGEXP '(?i)error'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "数值按字符串匹配",
+			whereClause: "status REGEXP '^5[0-9]{2}$'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "模式来自字段",
+			whereClause: "path REGEXP pattern",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "模式来自参数",
+			whereClause: "path REGEXP ?",
+			args:        []interface{}{"/orders/"},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "NULL不匹配",
+			whereClause: "trace REGEXP '.*' OR trace NOT REGEXP '.*'",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "无效的正则表达式",
+			whereClause: "path REGEXP '(unclosed'",
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "参数中的无效正则表达式",
+			whereClause: "path REGEXP ?",
+			args:        []interface{}{"[a-"},
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "超过长度限制",
+			whereClause: "path REGEXP 'orders/[0-9]+'",
+			opts:        []Option{WithMaxRegexpLength(8)},
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "参数超过长度限制",
+			whereClause: "path REGEXP ?",
+			args:        []interface{}{strings.Repeat("a", defaultMaxRegexpLength+1)},
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "不限制长度",
+			whereClause: "path REGEXP ?",
+			args:        []interface{}{strings.Repeat("(a)?", defaultMaxRegexpLength)},
+			opts:        []Option{WithMaxRegexpLength(0)},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "LIKE默认区分大小写",
+			whereClause: "level LIKE 'err%'",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "不区分大小写的LIKE",
+			whereClause: "level LIKE 'err%' AND path NOT LIKE '%/ORDERS/%1\\_'",
+			opts:        []Option{WithCaseInsensitiveLike()},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "不区分大小写的REGEXP",
+			whereClause: "level REGEXP '^err' AND level NOT RLIKE 'warn'",
+			opts:        []Option{WithCaseInsensitiveLike()},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "不区分大小写的REGEXP模式来自参数",
+			whereClause: "path REGEXP ?",
+			args:        []interface{}{"/ORDERS/[0-9]+$"},
+			opts:        []Option{WithCaseInsensitiveLike()},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "不区分大小写时仍可以用标志恢复区分大小写",
+			whereClause: "level REGEXP '(?-i)error'",
+			opts:        []Option{WithCaseInsensitiveLike()},
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "不区分大小写的LIKE模式来自参数",
+			whereClause: "path LIKE ?",
+			args:        []interface{}{"%/ORDERS/%"},
+			opts:        []Option{WithCaseInsensitiveLike()},
+			want:        true,
+			wantErr:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(logEntry, tt.opts...)
+			got, err := evaluator.EvaluateWhere(tt.whereClause, tt.args...)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileRegexp(t *testing.T) {
+	// 字面量正则表达式在编译时检查
+	if _, err := Compile("path REGEXP '(unclosed'"); err == nil {
+		t.Error("Compile() 无效的正则表达式 error = nil")
+	}
+	if _, err := Compile("path REGEXP 'abcdef'", WithMaxRegexpLength(5)); err == nil {
+		t.Error("Compile() 超过长度限制 error = nil")
+	}
+
+	rule := MustCompile("path REGEXP '^/api/' AND path LIKE '%ORDERS%'", WithCaseInsensitiveLike())
+	got, err := rule.Evaluate(map[string]interface{}{"path": "/api/orders"})
+	if err != nil || !got {
+		t.Errorf("Evaluate() = %v, %v, want true", got, err)
+	}
+}
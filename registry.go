@@ -0,0 +1,395 @@
+package sqlevaluator
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// functionNamePattern 自定义函数名的格式
+var functionNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var (
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+	timeType  = reflect.TypeOf(time.Time{})
+)
+
+// userFunction 注册的自定义函数
+type userFunction struct {
+	name string
+	fn   reflect.Value
+	// minArgs、maxArgs 参数个数范围，可变参数函数的 maxArgs 为-1
+	minArgs, maxArgs int
+	// returnsError 函数是否以error作为第二个返回值
+	returnsError bool
+}
+
+// newUserFunction 检查函数签名并创建自定义函数
+//
+// fn 必须是函数，参数类型为字符串、布尔、整数、浮点数、time.Time、interface{}或它们的指针，
+// 返回一个值，或者一个值和一个error。
+func newUserFunction(name string, fn interface{}) (*userFunction, error) {
+	if !functionNamePattern.MatchString(name) {
+		return nil, newInvalidArgument(reasonFunction, name, nil, msgFuncName, name)
+	}
+	lowered := strings.ToLower(name)
+	if _, ok := builtinFunctions[lowered]; ok || lowered == xorFuncName {
+		return nil, newInvalidArgument(reasonFunction, name, nil, msgFuncBuiltin, name)
+	}
+
+	fnValue := reflect.ValueOf(fn)
+	if fnValue.Kind() != reflect.Func || fnValue.IsNil() {
+		return nil, newInvalidArgument(reasonFunction, name, nil, msgFuncNotFunc, name, fmt.Sprintf("%T", fn))
+	}
+
+	fnType := fnValue.Type()
+	switch {
+	case fnType.NumOut() == 1 && fnType.Out(0) != errorType:
+	case fnType.NumOut() == 2 && fnType.Out(1) == errorType:
+	default:
+		return nil, newInvalidArgument(reasonFunction, name, nil, msgFuncResults, name)
+	}
+
+	for i := 0; i < fnType.NumIn(); i++ {
+		argType := fnType.In(i)
+		if fnType.IsVariadic() && i == fnType.NumIn()-1 {
+			argType = argType.Elem()
+		}
+		if !isSupportedArgType(argType) {
+			return nil, newInvalidArgument(reasonFunction, name, nil, msgFuncArgType, name, i+1, argType.String())
+		}
+	}
+
+	f := &userFunction{
+		name:         lowered,
+		fn:           fnValue,
+		minArgs:      fnType.NumIn(),
+		maxArgs:      fnType.NumIn(),
+		returnsError: fnType.NumOut() == 2,
+	}
+	if fnType.IsVariadic() {
+		f.minArgs--
+		f.maxArgs = -1
+	}
+	return f, nil
+}
+
+// isSupportedArgType 判断自定义函数的参数类型是否可以由SQL值转换得到
+func isSupportedArgType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Interface:
+		return t.NumMethod() == 0
+	default:
+		return false
+	}
+}
+
+// argType 返回第i个SQL参数对应的Go参数类型
+func (f *userFunction) argType(i int) reflect.Type {
+	fnType := f.fn.Type()
+	if fnType.IsVariadic() && i >= fnType.NumIn()-1 {
+		return fnType.In(fnType.NumIn() - 1).Elem()
+	}
+	return fnType.In(i)
+}
+
+// functionRegistry 自定义函数注册表
+type functionRegistry struct {
+	mu    sync.RWMutex
+	funcs map[string]*userFunction
+}
+
+// defaultRegistry 全局默认注册表，对所有评估器和Compile可见
+var defaultRegistry = &functionRegistry{funcs: make(map[string]*userFunction)}
+
+// register 注册函数，同名函数会被替换
+func (r *functionRegistry) register(f *userFunction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.funcs[f.name] = f
+}
+
+// lookup 按小写函数名查找函数
+func (r *functionRegistry) lookup(name string) (*userFunction, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f, ok := r.funcs[name]
+	return f, ok
+}
+
+// RegisterFunction 在全局默认注册表中注册自定义函数，注册后所有评估器和Compile都可以调用
+//
+// fn 的参数类型可以是字符串、布尔、整数、浮点数、time.Time、interface{}或它们的指针，支持可变参数；
+// 返回一个值，或者一个值和一个error。函数名不区分大小写，不能与内置函数重名，同名函数会被替换。
+// 子句编译时检查参数个数和字面量参数的类型；评估时非指针参数的值为NULL则不调用函数，结果为NULL。
+// 函数名或签名无效时返回 *InvalidArgumentError。
+//
+//	sqlevaluator.RegisterFunction("is_vip", func(level int) bool { return level >= 3 })
+func RegisterFunction(name string, fn interface{}) error {
+	f, err := newUserFunction(name, fn)
+	if err != nil {
+		return err
+	}
+	defaultRegistry.register(f)
+	return nil
+}
+
+// RegisterFunction 注册只对当前评估器可见的自定义函数，同名时优先于全局注册的函数
+//
+// 函数签名的要求与全局的RegisterFunction相同，错误信息使用评估器设置的语言。注册不是并发安全的，应在评估前完成。
+func (e *SQLEvaluator) RegisterFunction(name string, fn interface{}) error {
+	f, err := newUserFunction(name, fn)
+	if err != nil {
+		return resolveError(err, nil, e.options.language)
+	}
+	if e.functions == nil {
+		e.functions = make(map[string]*userFunction)
+	}
+	e.functions[f.name] = f
+	return nil
+}
+
+// resolveFunctions 检查子句中的函数调用并返回引用到的自定义函数
+//
+// local 为评估器注册的函数，优先于全局注册表。未知函数、参数个数错误以及字面量参数类型错误在此时报告。
+func resolveFunctions(expr sqlparser.Expr, local map[string]*userFunction, opts options) (map[string]*userFunction, error) {
+	var resolved map[string]*userFunction
+	err := sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		funcExpr, ok := node.(*sqlparser.FuncExpr)
+		if !ok || funcExpr.Name.EqualString(xorFuncName) {
+			return true, nil
+		}
+
+		name := funcExpr.Name.Lowered()
+		if funcExpr.Distinct || !funcExpr.Qualifier.IsEmpty() {
+			return false, withNode(newUnsupported(constructFunction, sqlparser.String(funcExpr)), funcExpr)
+		}
+		for _, selectExpr := range funcExpr.Exprs {
+			if _, ok := selectExpr.(*sqlparser.AliasedExpr); !ok {
+				return false, withNode(newUnsupported(constructArgument, sqlparser.String(selectExpr)), funcExpr)
+			}
+		}
+
+		if fn, ok := builtinFunctions[name]; ok {
+			return true, withNode(checkArity(name, fn.minArgs, fn.maxArgs, len(funcExpr.Exprs)), funcExpr)
+		}
+
+		f, ok := local[name]
+		if !ok {
+			f, ok = defaultRegistry.lookup(name)
+		}
+		if !ok {
+			return false, withNode(newUnsupported(constructFunction, sqlparser.String(funcExpr.Name)), funcExpr)
+		}
+		if err := checkArity(name, f.minArgs, f.maxArgs, len(funcExpr.Exprs)); err != nil {
+			return false, withNode(err, funcExpr)
+		}
+		if err := checkLiteralArgs(f, funcExpr, opts); err != nil {
+			return false, withNode(err, funcExpr)
+		}
+
+		if resolved == nil {
+			resolved = make(map[string]*userFunction)
+		}
+		resolved[name] = f
+		return true, nil
+	}, expr)
+	if err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// checkArity 检查函数调用的参数个数
+func checkArity(name string, minArgs, maxArgs, got int) error {
+	name = strings.ToUpper(name)
+	switch {
+	case maxArgs < 0 && got < minArgs:
+		return newInvalidArgument(reasonArity, name, nil, msgArityAtLeast, name, minArgs, got)
+	case maxArgs >= 0 && minArgs == maxArgs && got != minArgs:
+		return newInvalidArgument(reasonArity, name, nil, msgArity, name, minArgs, got)
+	case maxArgs >= 0 && (got < minArgs || got > maxArgs):
+		return newInvalidArgument(reasonArity, name, nil, msgArityRange, name, minArgs, maxArgs, got)
+	}
+	return nil
+}
+
+// checkLiteralArgs 检查字面量参数能否转换为函数的参数类型，列和表达式参数在评估时检查
+func checkLiteralArgs(f *userFunction, expr *sqlparser.FuncExpr, opts options) error {
+	e := &SQLEvaluator{options: opts}
+	for i, selectExpr := range expr.Exprs {
+		argExpr := selectExpr.(*sqlparser.AliasedExpr).Expr
+		switch node := argExpr.(type) {
+		case *sqlparser.SQLVal:
+			if node.Type == sqlparser.ValArg {
+				continue
+			}
+		case sqlparser.BoolVal:
+		default:
+			continue
+		}
+
+		value, err := e.getValue(argExpr)
+		if err != nil {
+			return err
+		}
+		if _, err := e.convertArg(value, f.argType(i)); err != nil {
+			return newArgumentError(f, i, err)
+		}
+	}
+	return nil
+}
+
+// callUserFunction 调用自定义函数
+func (e *SQLEvaluator) callUserFunction(f *userFunction, expr *sqlparser.FuncExpr) (interface{}, error) {
+	args := make([]reflect.Value, 0, len(expr.Exprs))
+	for i, selectExpr := range expr.Exprs {
+		value, err := e.getValue(selectExpr.(*sqlparser.AliasedExpr).Expr)
+		if err != nil {
+			return nil, err
+		}
+
+		argType := f.argType(i)
+		// 参数类型不能表示NULL时不调用函数，结果为NULL
+		if value == nil && argType.Kind() != reflect.Ptr && argType.Kind() != reflect.Interface {
+			return nil, nil
+		}
+
+		arg, err := e.convertArg(value, argType)
+		if err != nil {
+			return nil, newArgumentError(f, i, err)
+		}
+		args = append(args, arg)
+	}
+
+	results := f.fn.Call(args)
+	if f.returnsError && !results[1].IsNil() {
+		return nil, &FunctionError{Location: noLocation, Function: strings.ToUpper(f.name), Err: results[1].Interface().(error)}
+	}
+	return normalizeValue(results[0])
+}
+
+// newArgumentError 创建自定义函数第i个参数无法转换的错误
+func newArgumentError(f *userFunction, i int, err error) *InvalidArgumentError {
+	name := strings.ToUpper(f.name)
+	return newInvalidArgument(reasonArgument, name, err, msgArgument, name, i+1, err)
+}
+
+// convertArg 将SQL值转换为自定义函数的参数类型
+func (e *SQLEvaluator) convertArg(value interface{}, t reflect.Type) (reflect.Value, error) {
+	if value == nil {
+		return reflect.Zero(t), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return reflect.ValueOf(value), nil
+	case reflect.Ptr:
+		elem, err := e.convertArg(value, t.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	}
+
+	result := reflect.New(t).Elem()
+	if t == timeType {
+		switch v := value.(type) {
+		case time.Time:
+			result.Set(reflect.ValueOf(v))
+		case string:
+			// 未设置时区时按UTC解析不带时区的字面量
+			parsed, err := parseTime(v, e.timeLocation(time.Time{}))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			result.Set(reflect.ValueOf(parsed))
+		default:
+			return reflect.Value{}, newConversionError(value, typeTime, "")
+		}
+		return result, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		s, err := toString(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		result.SetString(s)
+	case reflect.Bool:
+		b, err := toBool(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		result.SetBool(b)
+	case reflect.Float32, reflect.Float64:
+		number, err := toNumber(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		result.SetFloat(toFloat(number))
+	default:
+		// 整数类型，不接受带小数部分或超出范围的值
+		number, err := toNumber(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if r, ok := number.(*big.Rat); ok {
+			if !r.IsInt() {
+				return reflect.Value{}, newConversionError(value, t.String(), "")
+			}
+			if number, err = fromBigInt(r.Num()); err != nil {
+				return reflect.Value{}, newOutOfRange(ratString(r))
+			}
+		}
+		if f, ok := number.(float64); ok {
+			if f != math.Trunc(f) {
+				return reflect.Value{}, newConversionError(value, t.String(), "")
+			}
+			if number, err = floatToInteger(f); err != nil {
+				return reflect.Value{}, newOutOfRange(value)
+			}
+		}
+
+		switch t.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			u, ok := number.(uint64)
+			if i, isInt := number.(int64); isInt {
+				u, ok = uint64(i), i >= 0
+			}
+			if !ok || result.OverflowUint(u) {
+				return reflect.Value{}, newOutOfRange(value)
+			}
+			result.SetUint(u)
+		default:
+			i, ok := number.(int64)
+			if !ok || result.OverflowInt(i) {
+				return reflect.Value{}, newOutOfRange(value)
+			}
+			result.SetInt(i)
+		}
+	}
+	return result, nil
+}
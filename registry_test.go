@@ -0,0 +1,271 @@
+package sqlevaluator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func init() {
+	// 全局注册的函数对所有评估器和Compile可见
+	if err := RegisterFunction("is_vip", func(level int) bool { return level >= 3 }); err != nil {
+		panic(err)
+	}
+	if err := RegisterFunction("in_region", func(ip string, region string) bool {
+		return region == "cn-east" && strings.HasPrefix(ip, "10.")
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// Visitor 自定义函数测试使用的访客模型
+type Visitor struct {
+	IP        string     `json:"ip"`
+	Level     int        `json:"level"`
+	Score     float64    `json:"score"`
+	Nickname  *string    `json:"nickname"`
+	LastLogin *time.Time `json:"last_login"`
+}
+
+func TestSQLEvaluatorRegisterFunction(t *testing.T) {
+	visitor := &Visitor{
+		IP:        "10.0.0.8",
+		Level:     3,
+		Score:     87.5,
+		LastLogin: timePtr(time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC)),
+	}
+
+	newEvaluator := func() *SQLEvaluator {
+		evaluator := NewSQLEvaluator(visitor)
+		mustRegister := func(name string, fn interface{}) {
+			if err := evaluator.RegisterFunction(name, fn); err != nil {
+				t.Fatalf("RegisterFunction(%s) error = %v", name, err)
+			}
+		}
+		mustRegister("grade", func(score float64) string {
+			if score >= 85 {
+				return "A"
+			}
+			return "B"
+		})
+		mustRegister("nick_or", func(nickname *string, fallback string) string {
+			if nickname == nil {
+				return fallback
+			}
+			return *nickname
+		})
+		mustRegister("sum_all", func(base int, values ...int) int {
+			for _, v := range values {
+				base += v
+			}
+			return base
+		})
+		mustRegister("days_before", func(t time.Time, deadline time.Time) (int, error) {
+			if t.After(deadline) {
+				return 0, errors.New("已过期")
+			}
+			return int(deadline.Sub(t).Hours() / 24), nil
+		})
+		// 评估器注册的函数优先于全局注册的同名函数
+		mustRegister("IS_VIP", func(level int) bool { return level >= 5 })
+		return evaluator
+	}
+
+	tests := []struct {
+		name        string
+		whereClause string
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "全局函数",
+			whereClause: "in_region(ip, 'cn-east')",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "评估器函数优先于全局函数",
+			whereClause: "is_vip(level)",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "函数结果参与比较",
+			whereClause: "grade(score) = 'A' AND level > 1",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "函数名不区分大小写",
+			whereClause: "GRADE(score) IN ('A', 'B')",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "指针参数接收NULL",
+			whereClause: "nick_or(nickname, 'guest') = 'guest'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "可变参数",
+			whereClause: "sum_all(level, 1, 2) = 6 AND sum_all(level) = 3",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "与内置函数嵌套",
+			whereClause: "LOWER(grade(ROUND(score))) = 'a'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "时间参数",
+			whereClause: "days_before(last_login, '2024-03-25') BETWEEN 4 AND 5",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "非指针参数为NULL时结果为NULL",
+			whereClause: "grade(NULL) IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "函数返回错误",
+			whereClause: "days_before(last_login, '2024-01-01') > 0",
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "参数个数错误",
+			whereClause: "grade(score, 1) = 'A'",
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "可变参数个数不足",
+			whereClause: "sum_all() = 0",
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "字面量参数类型错误",
+			whereClause: "sum_all(level, 'abc') = 0",
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "字面量参数不是整数",
+			whereClause: "is_vip(3.5)",
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "列参数类型错误",
+			whereClause: "sum_all(ip) = 0",
+			want:        false,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newEvaluator().EvaluateWhere(tt.whereClause)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterFunctionErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		fname string
+		fn    interface{}
+		want  string
+	}{
+		{
+			name:  "不是函数",
+			fname: "not_func",
+			fn:    1,
+			want:  `function not_func must be a non-nil func, got int`,
+		},
+		{
+			name:  "nil函数",
+			fname: "nil_func",
+			fn:    (func() bool)(nil),
+			want:  `function nil_func must be a non-nil func, got func() bool`,
+		},
+		{
+			name:  "无效的函数名",
+			fname: "bad-name",
+			fn:    func() bool { return true },
+			want:  `invalid function name: "bad-name"`,
+		},
+		{
+			name:  "与内置函数重名",
+			fname: "Lower",
+			fn:    func(s string) string { return s },
+			want:  `function Lower conflicts with a built-in function`,
+		},
+		{
+			name:  "没有返回值",
+			fname: "no_result",
+			fn:    func(s string) {},
+			want:  `function no_result must return a value, or a value and an error`,
+		},
+		{
+			name:  "第二个返回值不是error",
+			fname: "two_results",
+			fn:    func() (int, int) { return 0, 0 },
+			want:  `function two_results must return a value, or a value and an error`,
+		},
+		{
+			name:  "不支持的参数类型",
+			fname: "slice_arg",
+			fn:    func(s []string) bool { return true },
+			want:  `argument 1 of function slice_arg has unsupported type []string`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewSQLEvaluator(nil, WithLanguage(English)).RegisterFunction(tt.fname, tt.fn)
+			var invalid *InvalidArgumentError
+			if !errors.As(err, &invalid) || invalid.Code() != CodeInvalidArgument || err.Error() != tt.want {
+				t.Errorf("RegisterFunction() error = %v, want *InvalidArgumentError %q", err, tt.want)
+			}
+			if err := RegisterFunction(tt.fname, tt.fn); !errors.As(err, &invalid) {
+				t.Errorf("RegisterFunction() 全局注册 error = %v, want *InvalidArgumentError", err)
+			}
+		})
+	}
+}
+
+func TestCompileFunctions(t *testing.T) {
+	// 编译时检查全局函数的参数，未注册的函数在编译时报错
+	if _, err := Compile("is_vip(level, 1)"); err == nil {
+		t.Error("Compile() 参数个数错误 error = nil")
+	}
+	if _, err := Compile("not_registered(level)"); err == nil {
+		t.Error("Compile() 未注册函数 error = nil")
+	}
+	if _, err := Compile("LOWER()"); err == nil {
+		t.Error("Compile() 内置函数参数个数错误 error = nil")
+	}
+
+	rule := MustCompile("is_vip(level) AND in_region(ip, :region)")
+	got, err := rule.EvaluateNamed(&Visitor{IP: "10.1.1.1", Level: 3}, map[string]interface{}{"region": "cn-east"})
+	if err != nil || !got {
+		t.Errorf("EvaluateNamed() = %v, %v, want true", got, err)
+	}
+}
@@ -0,0 +1,196 @@
+package sqlevaluator
+
+import (
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// clauseToken WHERE子句中的词法单元
+type clauseToken struct {
+	typ        int
+	start, end int
+}
+
+// text 返回词法单元在原始子句中的文本
+func (t clauseToken) text(clause string) string {
+	return clause[t.start:t.end]
+}
+
+// clauseRewrite 基于词法单元的改写，没有需要改写的内容时原样返回子句
+type clauseRewrite func(clause string, tokens []clauseToken) string
+
+// clauseRewrites 解析前依次执行的改写
+var clauseRewrites = []clauseRewrite{rewriteLikeEscapes, rewriteXor, rewriteSubstring, rewriteListArgs}
+
+// rewriteClause 在解析前将sqlparser不支持的语法改写为等价形式
+//
+// 子句只做一次词法分析，某个改写修改了子句时才重新分析；不含相关语法的子句跳过词法分析。
+func rewriteClause(clause string) string {
+	if !mayNeedRewrite(clause) {
+		return clause
+	}
+	tokens, ok := scanTokens(clause)
+	if !ok {
+		return clause
+	}
+
+	for _, rewrite := range clauseRewrites {
+		rewritten := rewrite(clause, tokens)
+		if rewritten == clause {
+			continue
+		}
+		clause = rewritten
+		if tokens, ok = scanTokens(clause); !ok {
+			return clause
+		}
+	}
+	return clause
+}
+
+// mayNeedRewrite 粗略判断子句是否可能包含需要改写的语法：反斜杠转义、参数、XOR 或 SUBSTR
+func mayNeedRewrite(clause string) bool {
+	return strings.ContainsAny(clause, `\?:`) || containsFold(clause, "xor") || containsFold(clause, "substr")
+}
+
+// containsFold 判断 s 是否包含 substr，不区分大小写
+func containsFold(s, substr string) bool {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// scanTokens 将WHERE子句切分为词法单元，存在词法错误时返回false，交给解析阶段报告
+func scanTokens(clause string) ([]clauseToken, bool) {
+	tokens := make([]clauseToken, 0)
+
+	tokenizer := sqlparser.NewStringTokenizer(clause)
+	end := 0
+	for {
+		typ, _ := tokenizer.Scan()
+		if typ == 0 {
+			return tokens, true
+		}
+		if typ == sqlparser.LEX_ERROR {
+			return nil, false
+		}
+
+		// 词法单元结束后会多读入一个字符，结尾处除外
+		tokenEnd := tokenizer.Position - 1
+		if tokenEnd > len(clause) {
+			tokenEnd = len(clause)
+		}
+		start := end
+		for start < tokenEnd && isSpace(clause[start]) {
+			start++
+		}
+		end = tokenEnd
+
+		tokens = append(tokens, clauseToken{typ: typ, start: start, end: end})
+	}
+}
+
+// rewriteListArgs 将不带括号的列表参数 `IN :ids`、`IN ?` 改写为 `IN (:ids)`、`IN (?)`
+func rewriteListArgs(clause string, tokens []clauseToken) string {
+	var result strings.Builder
+	last := 0
+	for i := 1; i < len(tokens); i++ {
+		if tokens[i].typ != sqlparser.VALUE_ARG || tokens[i-1].typ != sqlparser.IN {
+			continue
+		}
+		result.WriteString(clause[last:tokens[i].start])
+		result.WriteString("(" + tokens[i].text(clause) + ")")
+		last = tokens[i].end
+	}
+
+	if last == 0 {
+		return clause
+	}
+	result.WriteString(clause[last:])
+	return result.String()
+}
+
+// rewriteSubstring 将第一个参数不是列名的 SUBSTRING(...)、SUBSTR(...) 改写为普通函数调用
+//
+// sqlparser的SUBSTRING语法只接受列名作为第一个参数，如 SUBSTRING(LOWER(name), 1, 2) 无法解析。
+// 第一个参数是列名时保留原语法，以支持 SUBSTRING(name FROM 2 FOR 3)。
+func rewriteSubstring(clause string, tokens []clauseToken) string {
+	var result strings.Builder
+	last := 0
+	for i := 0; i+1 < len(tokens); i++ {
+		if (tokens[i].typ != sqlparser.SUBSTRING && tokens[i].typ != sqlparser.SUBSTR) || tokens[i+1].typ != '(' {
+			continue
+		}
+		if isColumnArg(tokens[i+2:]) {
+			continue
+		}
+		result.WriteString(clause[last:tokens[i].start])
+		result.WriteString("`" + substringFuncName + "`")
+		last = tokens[i].end
+	}
+
+	if last == 0 {
+		return clause
+	}
+	result.WriteString(clause[last:])
+	return result.String()
+}
+
+// isColumnArg 判断词法单元是否以列名开头并紧跟 ',' 或 FROM
+func isColumnArg(tokens []clauseToken) bool {
+	i := 0
+	if i >= len(tokens) || tokens[i].typ != sqlparser.ID {
+		return false
+	}
+	for i+2 < len(tokens) && tokens[i+1].typ == '.' && tokens[i+2].typ == sqlparser.ID {
+		i += 2
+	}
+	return i+1 < len(tokens) && (tokens[i+1].typ == ',' || tokens[i+1].typ == sqlparser.FROM)
+}
+
+// rewriteLikeEscapes 保留字符串字面量中的 \% 和 \_
+//
+// sqlparser会把 '\%' 解码为 '%'，导致LIKE无法区分转义的 % 和通配符。MySQL中 \% 和 \_ 在字符串字面量中
+// 保持原样，由LIKE负责解释，因此这里将它们改写为 \\% 和 \\_，使解码结果为 \% 和 \_。
+func rewriteLikeEscapes(clause string, tokens []clauseToken) string {
+	var result strings.Builder
+	last := 0
+	for _, token := range tokens {
+		if token.typ != sqlparser.STRING {
+			continue
+		}
+		text := token.text(clause)
+		if !strings.Contains(text, `\%`) && !strings.Contains(text, `\_`) {
+			continue
+		}
+
+		result.WriteString(clause[last:token.start])
+		for i := 0; i < len(text); i++ {
+			if text[i] == '\\' && i+1 < len(text) {
+				if text[i+1] == '%' || text[i+1] == '_' {
+					result.WriteByte('\\')
+				} else {
+					// 其他转义序列原样保留，包括 \\
+					result.WriteByte(text[i])
+					i++
+				}
+			}
+			result.WriteByte(text[i])
+		}
+		last = token.end
+	}
+
+	if last == 0 {
+		return clause
+	}
+	result.WriteString(clause[last:])
+	return result.String()
+}
+
+// isSpace 判断字符是否为SQL空白字符
+func isSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
+}
@@ -0,0 +1,135 @@
+package sqlevaluator
+
+import "testing"
+
+func TestRewriteListArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		clause string
+		want   string
+	}{
+		{
+			name:   "不包含列表参数",
+			clause: "id IN (?, 2) AND name = :name",
+			want:   "id IN (?, 2) AND name = :name",
+		},
+		{
+			name:   "位置列表参数",
+			clause: "id IN ? AND age > ?",
+			want:   "id IN (?) AND age > ?",
+		},
+		{
+			name:   "命名列表参数",
+			clause: "id NOT IN :ids OR name IN  :names",
+			want:   "id NOT IN (:ids) OR name IN  (:names)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rewriteOnce(tt.clause, rewriteListArgs); got != tt.want {
+				t.Errorf("rewriteListArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewriteSubstring(t *testing.T) {
+	tests := []struct {
+		name   string
+		clause string
+		want   string
+	}{
+		{
+			name:   "列名参数保留原语法",
+			clause: "SUBSTRING(name, 1, 2) = 'ab' AND SUBSTR(u.name FROM 2) = 'b'",
+			want:   "SUBSTRING(name, 1, 2) = 'ab' AND SUBSTR(u.name FROM 2) = 'b'",
+		},
+		{
+			name:   "表达式参数",
+			clause: "SUBSTRING(LOWER(name), 1, 2) = 'ab'",
+			want:   "`substring`(LOWER(name), 1, 2) = 'ab'",
+		},
+		{
+			name:   "字面量参数",
+			clause: "substr('abc', 2) = name",
+			want:   "`substring`('abc', 2) = name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rewriteOnce(tt.clause, rewriteSubstring); got != tt.want {
+				t.Errorf("rewriteSubstring() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewriteLikeEscapes(t *testing.T) {
+	tests := []struct {
+		name   string
+		clause string
+		want   string
+	}{
+		{
+			name:   "不包含转义",
+			clause: `name LIKE 'a%' AND note = 'it\'s'`,
+			want:   `name LIKE 'a%' AND note = 'it\'s'`,
+		},
+		{
+			name:   "转义通配符",
+			clause: `name LIKE '50\%' OR code LIKE "A\_%"`,
+			want:   `name LIKE '50\\%' OR code LIKE "A\\_%"`,
+		},
+		{
+			name:   "转义的反斜杠后面是通配符",
+			clause: `name LIKE 'a\\%'`,
+			want:   `name LIKE 'a\\%'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rewriteOnce(tt.clause, rewriteLikeEscapes); got != tt.want {
+				t.Errorf("rewriteLikeEscapes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewriteClause(t *testing.T) {
+	tests := []struct {
+		name   string
+		clause string
+		want   string
+	}{
+		{
+			name:   "不需要改写",
+			clause: "age > 18 AND name = 'xor'",
+			want:   "age > 18 AND name = 'xor'",
+		},
+		{
+			name:   "多个改写依次执行",
+			clause: `is_active XOR SUBSTR(LOWER(name), 1, 2) = 'zh' AND id IN ? AND name LIKE 'a\%'`,
+			want:   "xor((is_active), (`substring` (LOWER (name) , 1 , 2) = 'zh' AND id IN (?) AND name LIKE 'a\\\\%'))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rewriteClause(tt.clause); got != tt.want {
+				t.Errorf("rewriteClause() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// rewriteOnce 对子句做词法分析后执行一次改写
+func rewriteOnce(clause string, rewrite clauseRewrite) string {
+	tokens, ok := scanTokens(clause)
+	if !ok {
+		return clause
+	}
+	return rewrite(clause, tokens)
+}
@@ -0,0 +1,121 @@
+package sqlevaluator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Schema 结构体模型的列描述，通常由 sqlevalgen 生成
+//
+// 列名按与反射读取字段相同的规则匹配：json 标签、字段名（不区分大小写）、下划线命名。
+type Schema struct {
+	modelType reflect.Type
+	fields    []SchemaField
+	names     []string
+	tags      []string
+	// columns 缓存匹配到字段的列名
+	columns sync.Map
+}
+
+// SchemaField 模型中可以作为列访问的字段，包括匿名嵌入结构体提升的字段
+type SchemaField struct {
+	// Name Go字段名
+	Name string
+	// Tag json 标签，如 `name,omitempty`
+	Tag string
+	// Type 字段类型，由 NewSchema 根据模型类型填充
+	Type reflect.Type
+}
+
+// SchemaProvider 可以提供列描述的模型，sqlevalgen 生成的 SQLSchema 方法实现了该接口
+type SchemaProvider interface {
+	SQLSchema() *Schema
+}
+
+// NewSchema 创建模型的列描述，model 为模型类型的值或指针（如 (*User)(nil)）
+//
+// fields 必须按 reflect.VisibleFields 的顺序列出模型的全部导出字段，字段不存在时panic。
+func NewSchema(model interface{}, fields []SchemaField) *Schema {
+	modelType := reflect.TypeOf(model)
+	for modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+
+	s := &Schema{
+		modelType: modelType,
+		fields:    make([]SchemaField, len(fields)),
+		names:     make([]string, len(fields)),
+		tags:      make([]string, len(fields)),
+	}
+	for i, field := range fields {
+		structField, ok := modelType.FieldByName(field.Name)
+		if !ok {
+			panic(fmt.Sprintf("sqlevaluator: %s 没有字段 %s", modelType, field.Name))
+		}
+		field.Type = structField.Type
+		s.fields[i] = field
+		s.names[i] = field.Name
+		s.tags[i] = field.Tag
+	}
+	return s
+}
+
+// Type 返回模型的结构体类型
+func (s *Schema) Type() reflect.Type {
+	return s.modelType
+}
+
+// Fields 返回模型的字段
+func (s *Schema) Fields() []SchemaField {
+	return s.fields
+}
+
+// Lookup 返回列名对应字段的下标，未匹配时返回-1
+func (s *Schema) Lookup(column string) int {
+	if cached, ok := s.columns.Load(column); ok {
+		return cached.(int)
+	}
+	i := matchColumn(s.names, s.tags, column)
+	// 只缓存匹配到字段的列名，不存在的列名不会使缓存增长
+	if i >= 0 {
+		s.columns.Store(column, i)
+	}
+	return i
+}
+
+// SchemaOf 通过反射生成结构体模型的列描述，model 为模型类型的值或指针（如 (*User)(nil)）
+func SchemaOf(model interface{}) *Schema {
+	modelType := reflect.TypeOf(model)
+	for modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+
+	var fields []SchemaField
+	for _, field := range reflect.VisibleFields(modelType) {
+		if field.IsExported() {
+			fields = append(fields, SchemaField{Name: field.Name, Tag: field.Tag.Get("json")})
+		}
+	}
+	return NewSchema(model, fields)
+}
+
+// columnType 返回列的Go类型，路径上有map的值为接口时返回nil
+func (s *Schema) columnType(column string) (reflect.Type, error) {
+	name, rest, nested := strings.Cut(column, ".")
+	i := s.Lookup(name)
+	if i < 0 {
+		// 第一级不是字段时按去掉限定名的列名解析，如 users.age
+		if nested {
+			if fieldType, err := s.columnType(rest); err == nil {
+				return fieldType, nil
+			}
+		}
+		return nil, newFieldNotFound(column)
+	}
+	if !nested {
+		return s.fields[i].Type, nil
+	}
+	return staticColumnType(s.fields[i].Type, column, 1)
+}
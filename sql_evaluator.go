@@ -1,49 +1,94 @@
 package sqlevaluator
 
 import (
+	"database/sql/driver"
 	"fmt"
+	"math/big"
 	"reflect"
-	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/xwb1989/sqlparser"
 )
 
 // SQLEvaluator SQL评估器
 type SQLEvaluator struct {
-	model interface{}
+	model   interface{}
+	options options
+	// params 本次评估绑定的参数，键为不含前导冒号的参数名
+	params map[string]interface{}
+	// args 本次评估按位置传入的参数个数
+	args int
+	// functions 通过评估器的RegisterFunction注册的自定义函数，键为小写的函数名
+	functions map[string]*userFunction
+	// compiled 本次评估的编译结果
+	compiled *CompiledWhere
+	// trace 记录评估过程，只在 EvaluateWhereExplain 中使用
+	trace *tracer
 }
 
 // NewSQLEvaluator 创建新的SQL评估器
-func NewSQLEvaluator(model interface{}) *SQLEvaluator {
+func NewSQLEvaluator(model interface{}, opts ...Option) *SQLEvaluator {
 	return &SQLEvaluator{
-		model: model,
+		model:   model,
+		options: newOptions(opts),
 	}
 }
 
 // EvaluateWhere 评估WHERE子句
-func (e *SQLEvaluator) EvaluateWhere(whereClause string) (bool, error) {
-	// 解析SQL
-	stmt, err := sqlparser.Parse("SELECT * FROM `users` WHERE " + whereClause)
+//
+// args 按顺序绑定子句中的 ? 占位符，切片参数在IN列表中展开，如 `id IN ?`。
+// 每次调用都会重新解析WHERE子句，需要对大量模型重复评估同一条件时请使用Compile。
+func (e *SQLEvaluator) EvaluateWhere(whereClause string, args ...interface{}) (bool, error) {
+	params, err := positionalParams(args)
 	if err != nil {
-		return false, fmt.Errorf("解析SQL失败: %v", err)
+		return false, resolveError(err, nil, e.options.language)
 	}
+	return e.evaluateWhereWithParams(whereClause, params, len(args))
+}
 
-	selectStmt, ok := stmt.(*sqlparser.Select)
-	if !ok {
-		return false, fmt.Errorf("不是SELECT语句")
+// EvaluateWhereNamed 使用命名参数评估WHERE子句
+//
+// params 绑定子句中的 :name 占位符，参数名可以带或不带前导冒号。
+func (e *SQLEvaluator) EvaluateWhereNamed(whereClause string, params map[string]interface{}) (bool, error) {
+	bound, err := namedParams(params)
+	if err != nil {
+		return false, resolveError(err, nil, e.options.language)
 	}
+	return e.evaluateWhereWithParams(whereClause, bound, 0)
+}
 
-	if selectStmt.Where == nil {
-		return true, nil
+// evaluateWhereWithParams 绑定参数后评估WHERE子句，不修改评估器本身，args 为按位置传入的参数个数
+func (e *SQLEvaluator) evaluateWhereWithParams(whereClause string, params map[string]interface{}, args int) (bool, error) {
+	compiled, err := compile(whereClause, e.options, e.functions)
+	if err != nil {
+		return false, err
 	}
 
-	return e.evaluateExpr(selectStmt.Where.Expr)
+	bound := *e
+	bound.params, bound.args = params, args
+	return bound.evaluateCompiled(compiled)
+}
+
+// evaluateExpr 评估表达式，结构化错误关联到产生错误的表达式
+func (e *SQLEvaluator) evaluateExpr(expr sqlparser.Expr) (truth, error) {
+	var node *ExplainNode
+	if e.trace != nil {
+		node = e.trace.enter(e, expr)
+	}
+	result, err := e.evaluateCondition(expr)
+	if node != nil {
+		e.trace.leave(node, result)
+	}
+	if err != nil {
+		return truthFalse, withNode(err, expr)
+	}
+	return result, nil
 }
 
-// evaluateExpr 评估表达式
-func (e *SQLEvaluator) evaluateExpr(expr sqlparser.Expr) (bool, error) {
+// evaluateCondition 按表达式类型评估条件
+func (e *SQLEvaluator) evaluateCondition(expr sqlparser.Expr) (truth, error) {
 	switch node := expr.(type) {
 	case *sqlparser.ComparisonExpr:
 		if node.Operator == sqlparser.InStr || node.Operator == sqlparser.NotInStr {
@@ -51,248 +96,279 @@ func (e *SQLEvaluator) evaluateExpr(expr sqlparser.Expr) (bool, error) {
 		}
 		return e.evaluateComparison(node)
 	case *sqlparser.AndExpr:
+		// 短路求值：左侧为FALSE时结果已确定，不评估右侧，右侧的错误也不会返回
 		left, err := e.evaluateExpr(node.Left)
-		if err != nil {
-			return false, err
+		if err != nil || left == truthFalse {
+			return truthFalse, err
 		}
 		right, err := e.evaluateExpr(node.Right)
 		if err != nil {
-			return false, err
+			return truthFalse, err
 		}
-		return left && right, nil
+		return left.and(right), nil
 	case *sqlparser.OrExpr:
+		// 短路求值：左侧为TRUE时结果已确定，不评估右侧
 		left, err := e.evaluateExpr(node.Left)
 		if err != nil {
-			return false, err
+			return truthFalse, err
+		}
+		if left == truthTrue {
+			return truthTrue, nil
 		}
 		right, err := e.evaluateExpr(node.Right)
 		if err != nil {
-			return false, err
+			return truthFalse, err
 		}
-		return left || right, nil
+		return left.or(right), nil
+	case *sqlparser.NotExpr:
+		result, err := e.evaluateExpr(node.Expr)
+		if err != nil {
+			return truthFalse, err
+		}
+		return result.not(), nil
 	case *sqlparser.ParenExpr:
 		return e.evaluateExpr(node.Expr)
 	case *sqlparser.RangeCond:
 		return e.evaluateRange(node)
 	case *sqlparser.IsExpr:
 		return e.evaluateIsExpr(node)
+	case *sqlparser.UnaryExpr:
+		// 处理 !expr，与 NOT 相同
+		if node.Operator == sqlparser.BangStr {
+			result, err := e.evaluateExpr(node.Expr)
+			if err != nil {
+				return truthFalse, err
+			}
+			return result.not(), nil
+		}
+		return e.evaluateTruthValue(node)
+	case *sqlparser.FuncExpr:
+		if node.Name.EqualString(xorFuncName) {
+			return e.evaluateXor(node)
+		}
+		return e.evaluateTruthValue(node)
+	case *sqlparser.ColName, sqlparser.BoolVal, *sqlparser.SQLVal, *sqlparser.NullVal, *sqlparser.BinaryExpr, *sqlparser.SubstrExpr, *sqlparser.CaseExpr:
+		// 单独出现的列、字面量、算术表达式或函数调用作为条件，如 WHERE is_active
+		return e.evaluateTruthValue(node)
 	default:
-		return false, fmt.Errorf("不支持的表达式类型: %T", expr)
+		return truthFalse, newUnsupportedExpr(expr)
 	}
 }
 
-// evaluateComparison 评估比较表达式
-func (e *SQLEvaluator) evaluateComparison(expr *sqlparser.ComparisonExpr) (bool, error) {
-	// 获取左操作数的字段名
-	leftField, err := e.getFieldName(expr.Left)
-	if err != nil {
-		return false, err
-	}
-
-	// 获取左操作数的值
-	leftVal, err := e.getFieldValue(leftField)
-	if err != nil {
-		return false, err
+// evaluateXor 评估XOR运算，操作数中为真的个数为奇数时结果为真
+func (e *SQLEvaluator) evaluateXor(expr *sqlparser.FuncExpr) (truth, error) {
+	if len(expr.Exprs) < 2 {
+		return truthFalse, newInvalidArgument(reasonOperands, "XOR", nil, msgXorOperands)
 	}
 
-	// 处理IS NULL和IS NOT NULL
-	if sqlVal, ok := expr.Right.(*sqlparser.SQLVal); ok && sqlVal.Type == 0 {
-		switch expr.Operator {
-		case "is null":
-			return leftVal == nil, nil
-		case "is not null":
-			return leftVal != nil, nil
+	result := truthFalse
+	for _, selectExpr := range expr.Exprs {
+		aliased, ok := selectExpr.(*sqlparser.AliasedExpr)
+		if !ok {
+			return truthFalse, newUnsupported(constructArgument, sqlparser.String(selectExpr))
 		}
+		operand, err := e.evaluateExpr(aliased.Expr)
+		if err != nil {
+			return truthFalse, err
+		}
+		result = result.xor(operand)
 	}
+	return result, nil
+}
 
-	// 如果左操作数为NULL，且不是IS NULL或IS NOT NULL操作，则返回false
-	if leftVal == nil {
-		return false, nil
+// evaluateTruthValue 将列或字面量的值作为条件评估
+func (e *SQLEvaluator) evaluateTruthValue(expr sqlparser.Expr) (truth, error) {
+	result, err := e.getTruthValue(expr)
+	if err != nil {
+		return truthFalse, err
 	}
+	if result == truthUnknown {
+		return e.nullResult(), nil
+	}
+	return result, nil
+}
 
-	// 处理IN和NOT IN操作符
-	if expr.Operator == sqlparser.InStr || expr.Operator == sqlparser.NotInStr {
-		// 获取IN列表的值
-		values, err := e.getSQLValues(expr.Right)
+// getTruthValue 获取表达式的真值，值为NULL时返回UNKNOWN
+func (e *SQLEvaluator) getTruthValue(expr sqlparser.Expr) (truth, error) {
+	var val interface{}
+	var err error
+	switch node := expr.(type) {
+	case *sqlparser.ParenExpr:
+		return e.getTruthValue(node.Expr)
+	case *sqlparser.ColName, *sqlparser.SQLVal, sqlparser.BoolVal, *sqlparser.NullVal, *sqlparser.BinaryExpr, *sqlparser.SubstrExpr, *sqlparser.CaseExpr:
+		val, err = e.getValue(node)
 		if err != nil {
-			return false, err
-		}
-
-		// 检查左操作数是否在值列表中
-		found := false
-		for _, val := range values {
-			// 如果值为NULL，跳过
-			if val == nil {
-				continue
-			}
-
-			// 尝试类型转换
-			leftConverted, rightConverted, err := convertTypes(leftVal, val)
-			if err == nil && reflect.DeepEqual(leftConverted, rightConverted) {
-				found = true
-				break
-			}
+			return truthFalse, err
 		}
-
-		// 对于NOT IN，如果找到匹配项则返回false，否则返回true
-		if expr.Operator == sqlparser.NotInStr {
-			return !found, nil
+	case *sqlparser.FuncExpr:
+		if node.Name.EqualString(xorFuncName) {
+			return e.evaluateExpr(node)
 		}
-		// 对于IN，如果找到匹配项则返回true，否则返回false
-		return found, nil
-	}
-
-	// 处理BETWEEN和NOT BETWEEN操作符
-	if expr.Operator == sqlparser.BetweenStr || expr.Operator == sqlparser.NotBetweenStr {
-		// 获取范围值
-		values, err := e.getSQLValues(expr.Right)
+		val, err = e.getValue(node)
 		if err != nil {
-			return false, err
+			return truthFalse, err
 		}
-
-		if len(values) != 2 {
-			return false, fmt.Errorf("BETWEEN操作符需要两个值")
-		}
-
-		// 确保所有值都是相同类型
-		lower := values[0]
-		upper := values[1]
-
-		// 如果范围值为NULL，则返回false
-		if lower == nil || upper == nil {
-			return false, nil
+	case *sqlparser.UnaryExpr:
+		if node.Operator == sqlparser.BangStr {
+			return e.evaluateExpr(node)
 		}
-
-		// 尝试类型转换
-		leftLower, lowerConverted, err := convertTypes(leftVal, lower)
+		val, err = e.getValue(node)
 		if err != nil {
-			return false, err
+			return truthFalse, err
 		}
+	default:
+		// 比较、逻辑运算等条件表达式
+		return e.evaluateExpr(expr)
+	}
 
-		leftUpper, upperConverted, err := convertTypes(leftVal, upper)
-		if err != nil {
-			return false, err
-		}
+	e.traceLeft(val)
+	if val == nil {
+		return truthUnknown, nil
+	}
+	value, err := toBool(val)
+	if err != nil {
+		return truthFalse, err
+	}
+	return truthOf(value), nil
+}
 
-		// 比较值是否在范围内
-		fromResult, err := compareValues(leftLower, lowerConverted, func(a, b float64) bool { return a >= b })
-		if err != nil {
-			return false, err
+// toBool 将值转换为布尔值，数值非零为真
+func toBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case int64:
+		return v != 0, nil
+	case uint64:
+		return v != 0, nil
+	case float64:
+		return v != 0, nil
+	case *big.Rat:
+		return v.Sign() != 0, nil
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b, nil
 		}
-
-		toResult, err := compareValues(leftUpper, upperConverted, func(a, b float64) bool { return a <= b })
-		if err != nil {
-			return false, err
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f != 0, nil
 		}
+		return false, newConversionError(v, typeBool, "")
+	default:
+		return false, newConversionError(value, typeBool, "")
+	}
+}
 
-		result := fromResult && toResult
-		// 对于NOT BETWEEN，如果不在范围内则返回true，否则返回false
-		if expr.Operator == sqlparser.NotBetweenStr {
-			return !result, nil
-		}
-		// 对于BETWEEN，如果在范围内则返回true，否则返回false
-		return result, nil
+// evaluateComparison 评估比较表达式
+func (e *SQLEvaluator) evaluateComparison(expr *sqlparser.ComparisonExpr) (truth, error) {
+	// 获取左操作数的值，左操作数可以是列或算术表达式
+	leftVal, err := e.getValue(expr.Left)
+	if err != nil {
+		return truthFalse, err
+	}
+	e.traceLeft(leftVal)
+
+	// 如果左操作数为NULL，则比较结果为UNKNOWN（默认模式下为false）
+	if leftVal == nil {
+		return e.nullResult(), nil
 	}
 
 	// 获取右操作数的值
 	rightVal, err := e.getValue(expr.Right)
 	if err != nil {
-		return false, err
+		return truthFalse, err
 	}
+	e.traceRight(rightVal)
 
-	// 如果右操作数为NULL，且不是IS NULL或IS NOT NULL操作，则返回false
+	// 如果右操作数为NULL，则比较结果为UNKNOWN（默认模式下为false）
 	if rightVal == nil {
-		return false, nil
+		return e.nullResult(), nil
+	}
+
+	// LIKE和REGEXP按字符串匹配，不做类型转换
+	if isLikeOperator(expr.Operator) {
+		matched, err := e.evaluateLike(expr, leftVal, rightVal)
+		if err != nil {
+			return truthFalse, err
+		}
+		if expr.Operator == sqlparser.NotLikeStr {
+			matched = !matched
+		}
+		return truthOf(matched), nil
+	}
+	if isRegexpOperator(expr.Operator) {
+		matched, err := e.evaluateRegexp(expr, leftVal, rightVal)
+		if err != nil {
+			return truthFalse, err
+		}
+		if expr.Operator == sqlparser.NotRegexpStr {
+			matched = !matched
+		}
+		return truthOf(matched), nil
 	}
 
 	// 尝试类型转换
-	leftConverted, rightConverted, err := convertTypes(leftVal, rightVal)
+	leftConverted, rightConverted, err := e.convertTypes(leftVal, rightVal, expr.Operator)
 	if err != nil {
-		return false, err
+		return truthFalse, err
 	}
+	e.traceLeft(leftConverted)
+	e.traceRight(rightConverted)
 
 	// 根据操作符进行比较
+	var result bool
 	switch expr.Operator {
 	case "=":
-		return reflect.DeepEqual(leftConverted, rightConverted), nil
+		result = valuesEqual(leftConverted, rightConverted)
 	case "!=", "<>":
-		return !reflect.DeepEqual(leftConverted, rightConverted), nil
+		result = !valuesEqual(leftConverted, rightConverted)
 	case ">":
-		return compareValues(leftConverted, rightConverted, func(a, b float64) bool { return a > b })
+		result, err = compareValues(leftConverted, rightConverted, expr.Operator, func(c int) bool { return c > 0 })
 	case ">=":
-		return compareValues(leftConverted, rightConverted, func(a, b float64) bool { return a >= b })
+		result, err = compareValues(leftConverted, rightConverted, expr.Operator, func(c int) bool { return c >= 0 })
 	case "<":
-		return compareValues(leftConverted, rightConverted, func(a, b float64) bool { return a < b })
+		result, err = compareValues(leftConverted, rightConverted, expr.Operator, func(c int) bool { return c < 0 })
 	case "<=":
-		return compareValues(leftConverted, rightConverted, func(a, b float64) bool { return a <= b })
-	case "like":
-		return e.evaluateLike(leftConverted, rightConverted)
-	case "not like":
-		result, err := e.evaluateLike(leftConverted, rightConverted)
-		if err != nil {
-			return false, err
-		}
-		return !result, nil
+		result, err = compareValues(leftConverted, rightConverted, expr.Operator, func(c int) bool { return c <= 0 })
 	default:
-		return false, fmt.Errorf("不支持的操作符: %s", expr.Operator)
+		return truthFalse, newUnsupported(constructOperator, expr.Operator)
 	}
-}
-
-// evaluateLike 评估LIKE操作符
-func (e *SQLEvaluator) evaluateLike(left, right interface{}) (bool, error) {
-	leftStr, ok := left.(string)
-	if !ok {
-		return false, fmt.Errorf("LIKE操作符的左侧必须是字符串类型")
-	}
-
-	rightStr, ok := right.(string)
-	if !ok {
-		return false, fmt.Errorf("LIKE操作符的右侧必须是字符串类型")
-	}
-
-	// 将SQL LIKE模式转换为正则表达式
-	pattern := strings.ReplaceAll(rightStr, "%", ".*")
-	pattern = strings.ReplaceAll(pattern, "_", ".")
-	pattern = "^" + pattern + "$"
-
-	matched, err := regexp.MatchString(pattern, leftStr)
 	if err != nil {
-		return false, fmt.Errorf("正则表达式匹配失败: %v", err)
+		return truthFalse, err
 	}
-
-	return matched, nil
+	return truthOf(result), nil
 }
 
 // evaluateInExpr 评估IN表达式
-func (e *SQLEvaluator) evaluateInExpr(expr *sqlparser.ComparisonExpr) (bool, error) {
-	// 获取左操作数的字段名
-	leftField, err := e.getFieldName(expr.Left)
+//
+// 三值逻辑模式下，列表中包含NULL且没有匹配项时结果为UNKNOWN，因此 x NOT IN (1, NULL) 不会为真。
+func (e *SQLEvaluator) evaluateInExpr(expr *sqlparser.ComparisonExpr) (truth, error) {
+	// 获取左操作数的值，左操作数可以是列或算术表达式
+	leftVal, err := e.getValue(expr.Left)
 	if err != nil {
-		return false, err
+		return truthFalse, err
 	}
 
-	// 获取左操作数的值
-	leftVal, err := e.getFieldValue(leftField)
-	if err != nil {
-		return false, err
-	}
+	e.traceLeft(leftVal)
 
-	// 如果左操作数为NULL，则返回false
+	// 如果左操作数为NULL，则结果为UNKNOWN（默认模式下为false）
 	if leftVal == nil {
-		return false, nil
+		return e.nullResult(), nil
 	}
 
 	// 获取IN列表的值
 	values, err := e.getSQLValues(expr.Right)
 	if err != nil {
-		return false, err
+		return truthFalse, err
 	}
+	e.traceRight(values)
 
 	// 检查左操作数是否在值列表中
 	found := false
+	hasNull := false
 	for _, val := range values {
 		// 如果值为NULL，跳过
 		if val == nil {
+			hasNull = true
 			continue
 		}
 
@@ -302,369 +378,465 @@ func (e *SQLEvaluator) evaluateInExpr(expr *sqlparser.ComparisonExpr) (bool, err
 				// 获取一元表达式的值
 				actualVal, err := e.getValue(unaryVal.Expr)
 				if err != nil {
-					return false, err
+					return truthFalse, err
 				}
 				// 根据类型处理负数
-				switch v := actualVal.(type) {
-				case int:
-					val = -v
-				case float64:
-					val = -v
-				default:
-					return false, fmt.Errorf("不支持的一元表达式类型: %T", actualVal)
+				val, err = negate(actualVal)
+				if err != nil {
+					return truthFalse, withNode(err, unaryVal)
 				}
 			}
 		}
 
 		// 尝试类型转换
-		leftConverted, rightConverted, err := convertTypes(leftVal, val)
-		if err == nil && reflect.DeepEqual(leftConverted, rightConverted) {
+		leftConverted, rightConverted, err := e.convertTypes(leftVal, val, sqlparser.EqualStr)
+		if err == nil && valuesEqual(leftConverted, rightConverted) {
 			found = true
 			break
 		}
 	}
 
+	// 没有匹配项但列表中有NULL时，无法确定左操作数是否在列表中
+	if !found && hasNull && e.options.threeValuedLogic {
+		return truthUnknown, nil
+	}
+
 	// 对于NOT IN，如果找到匹配项则返回false，否则返回true
 	if expr.Operator == sqlparser.NotInStr {
-		return !found, nil
+		return truthOf(!found), nil
 	}
 	// 对于IN，如果找到匹配项则返回true，否则返回false
-	return found, nil
+	return truthOf(found), nil
 }
 
 // evaluateIsExpr 评估IS表达式
-func (e *SQLEvaluator) evaluateIsExpr(expr *sqlparser.IsExpr) (bool, error) {
-	// 获取左操作数的字段名
-	leftField, err := e.getFieldName(expr.Expr)
-	if err != nil {
-		return false, err
+func (e *SQLEvaluator) evaluateIsExpr(expr *sqlparser.IsExpr) (truth, error) {
+	// IS TRUE / IS FALSE 及其否定形式适用于任意条件，NULL既不是TRUE也不是FALSE
+	switch expr.Operator {
+	case sqlparser.IsTrueStr, sqlparser.IsNotTrueStr, sqlparser.IsFalseStr, sqlparser.IsNotFalseStr:
+		value, err := e.getTruthValue(expr.Expr)
+		if err != nil {
+			return truthFalse, err
+		}
+		switch expr.Operator {
+		case sqlparser.IsTrueStr:
+			return truthOf(value == truthTrue), nil
+		case sqlparser.IsNotTrueStr:
+			return truthOf(value != truthTrue), nil
+		case sqlparser.IsFalseStr:
+			return truthOf(value == truthFalse), nil
+		default:
+			return truthOf(value != truthFalse), nil
+		}
 	}
 
 	// 获取左操作数的值
-	leftVal, err := e.getFieldValue(leftField)
+	leftVal, err := e.getValue(expr.Expr)
 	if err != nil {
-		return false, err
+		return truthFalse, err
 	}
+	e.traceLeft(leftVal)
 
 	// 根据操作符返回结果
 	switch expr.Operator {
 	case "is null":
-		return leftVal == nil, nil
+		return truthOf(leftVal == nil), nil
 	case "is not null":
-		return leftVal != nil, nil
-	default:
-		return false, fmt.Errorf("不支持的IS操作符: %s", expr.Operator)
-	}
-}
-
-// evaluateBetween 评估BETWEEN操作符
-func (e *SQLEvaluator) evaluateBetween(left, right interface{}) (bool, error) {
-	values, ok := right.([]interface{})
-	if !ok || len(values) != 2 {
-		return false, fmt.Errorf("BETWEEN操作符需要两个值")
-	}
-
-	lower := values[0]
-	upper := values[1]
-
-	// 确保所有值都是相同类型
-	switch v := left.(type) {
-	case int:
-		lowerInt, ok1 := lower.(int)
-		upperInt, ok2 := upper.(int)
-		if !ok1 || !ok2 {
-			return false, fmt.Errorf("BETWEEN操作符的值类型必须一致")
-		}
-		return v >= lowerInt && v <= upperInt, nil
-	case float64:
-		lowerFloat, ok1 := lower.(float64)
-		upperFloat, ok2 := upper.(float64)
-		if !ok1 || !ok2 {
-			return false, fmt.Errorf("BETWEEN操作符的值类型必须一致")
-		}
-		return v >= lowerFloat && v <= upperFloat, nil
-	case string:
-		lowerStr, ok1 := lower.(string)
-		upperStr, ok2 := upper.(string)
-		if !ok1 || !ok2 {
-			return false, fmt.Errorf("BETWEEN操作符的值类型必须一致")
-		}
-		return v >= lowerStr && v <= upperStr, nil
+		return truthOf(leftVal != nil), nil
 	default:
-		return false, fmt.Errorf("不支持的BETWEEN值类型: %T", left)
+		return truthFalse, newUnsupported(constructOperator, expr.Operator)
 	}
 }
 
 // evaluateRange 评估范围条件（BETWEEN）
-func (e *SQLEvaluator) evaluateRange(expr *sqlparser.RangeCond) (bool, error) {
-	// 获取左操作数的字段名
-	leftField, err := e.getFieldName(expr.Left)
+//
+// 三值逻辑模式下按 x >= from AND x <= to 计算，范围值为NULL时另一侧的比较仍可能决定结果。
+func (e *SQLEvaluator) evaluateRange(expr *sqlparser.RangeCond) (truth, error) {
+	// 获取左操作数的值，左操作数可以是列或算术表达式
+	leftVal, err := e.getValue(expr.Left)
 	if err != nil {
-		return false, err
+		return truthFalse, err
 	}
 
-	// 获取左操作数的值
-	leftVal, err := e.getFieldValue(leftField)
-	if err != nil {
-		return false, err
-	}
+	e.traceLeft(leftVal)
 
-	// 如果左操作数为NULL，则返回false
+	// 如果左操作数为NULL，则结果为UNKNOWN（默认模式下为false）
 	if leftVal == nil {
-		return false, nil
+		return e.nullResult(), nil
 	}
 
 	// 获取范围的最小值和最大值
 	fromVal, err := e.getValue(expr.From)
 	if err != nil {
-		return false, err
+		return truthFalse, err
 	}
 
 	toVal, err := e.getValue(expr.To)
 	if err != nil {
-		return false, err
+		return truthFalse, err
 	}
+	e.traceRight([]interface{}{fromVal, toVal})
 
-	// 如果范围值为NULL，则返回false
-	if fromVal == nil || toVal == nil {
-		return false, nil
-	}
-
-	// 尝试类型转换
-	leftLower, lowerConverted, err := convertTypes(leftVal, fromVal)
-	if err != nil {
-		return false, err
-	}
-
-	leftUpper, upperConverted, err := convertTypes(leftVal, toVal)
-	if err != nil {
-		return false, err
+	// 默认模式下，如果范围值为NULL，则返回false
+	if (fromVal == nil || toVal == nil) && !e.options.threeValuedLogic {
+		return truthFalse, nil
 	}
 
 	// 比较值是否在范围内
-	fromResult, err := compareValues(leftLower, lowerConverted, func(a, b float64) bool { return a >= b })
+	fromResult, err := e.compareBound(leftVal, fromVal, sqlparser.GreaterEqualStr, func(c int) bool { return c >= 0 })
 	if err != nil {
-		return false, err
+		return truthFalse, withNode(err, expr.From)
 	}
 
-	toResult, err := compareValues(leftUpper, upperConverted, func(a, b float64) bool { return a <= b })
+	toResult, err := e.compareBound(leftVal, toVal, sqlparser.LessEqualStr, func(c int) bool { return c <= 0 })
 	if err != nil {
-		return false, err
+		return truthFalse, withNode(err, expr.To)
 	}
 
-	result := fromResult && toResult
+	result := fromResult.and(toResult)
 	// 对于NOT BETWEEN，如果不在范围内则返回true，否则返回false
 	if expr.Operator == sqlparser.NotBetweenStr {
-		return !result, nil
+		return result.not(), nil
 	}
 	// 对于BETWEEN，如果在范围内则返回true，否则返回false
 	return result, nil
 }
 
-// getValue 获取表达式的值
+// compareBound 将值与BETWEEN的一个边界比较，边界为NULL时结果为UNKNOWN
+func (e *SQLEvaluator) compareBound(value, bound interface{}, operator string, compare func(int) bool) (truth, error) {
+	if bound == nil {
+		return truthUnknown, nil
+	}
+
+	// 尝试类型转换
+	valueConverted, boundConverted, err := e.convertTypes(value, bound, operator)
+	if err != nil {
+		return truthFalse, err
+	}
+
+	result, err := compareValues(valueConverted, boundConverted, operator, compare)
+	if err != nil {
+		return truthFalse, err
+	}
+	return truthOf(result), nil
+}
+
+// getValue 获取表达式的值，结构化错误关联到产生错误的表达式
 func (e *SQLEvaluator) getValue(expr sqlparser.Expr) (interface{}, error) {
+	value, err := e.getExprValue(expr)
+	if err != nil {
+		return nil, withNode(err, expr)
+	}
+	return value, nil
+}
+
+// getExprValue 按表达式类型获取值
+func (e *SQLEvaluator) getExprValue(expr sqlparser.Expr) (interface{}, error) {
 	switch node := expr.(type) {
 	case *sqlparser.ColName:
-		return e.getFieldValue(node.Name.String())
+		// 列可以出现在比较的任意一侧，与左操作数使用相同的解析规则
+		value, err := e.getColumnValue(node)
+		if err != nil {
+			return nil, err
+		}
+		return e.exactNumber(value), nil
 	case *sqlparser.SQLVal:
 		switch node.Type {
 		case sqlparser.StrVal:
 			return string(node.Val), nil
 		case sqlparser.IntVal:
-			// 将字符串转换为整数
-			var val int
-			_, err := fmt.Sscanf(string(node.Val), "%d", &val)
-			if err != nil {
-				return nil, fmt.Errorf("无法解析整数值: %v", err)
+			// 整数字面量解析为int64，超出范围时解析为uint64，
+			// 超出uint64范围时与MySQL一样按十进制数值处理
+			val, ok := parseNumber(string(node.Val))
+			if !ok {
+				return nil, newInvalidArgument(reasonLiteral, string(node.Val), nil, msgIntegerLiteral, string(node.Val))
+			}
+			if !isInteger(val) && e.options.exactDecimal {
+				if r, ok := new(big.Rat).SetString(string(node.Val)); ok {
+					return r, nil
+				}
 			}
 			return val, nil
 		case sqlparser.FloatVal:
+			// 精确十进制模式下按十进制数值解析，如 0.1 解析为 1/10
+			if e.options.exactDecimal {
+				if r, ok := new(big.Rat).SetString(string(node.Val)); ok {
+					return r, nil
+				}
+			}
 			// 将字符串转换为浮点数
 			var val float64
 			_, err := fmt.Sscanf(string(node.Val), "%f", &val)
 			if err != nil {
-				return nil, fmt.Errorf("无法解析浮点数值: %v", err)
+				return nil, newInvalidArgument(reasonLiteral, string(node.Val), err, msgFloatLiteral, string(node.Val))
 			}
 			return val, nil
 		case sqlparser.ValArg:
-			return string(node.Val), nil
+			value, err := e.getParam(strings.TrimPrefix(string(node.Val), ":"))
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := value.([]interface{}); ok {
+				return nil, newParamError(strings.TrimPrefix(string(node.Val), ":"), paramList, nil)
+			}
+			return e.exactNumber(value), nil
 		default:
-			return nil, fmt.Errorf("不支持的SQL值类型: %v", node.Type)
+			return nil, newUnsupported(constructValue, sqlparser.String(node))
 		}
 	case sqlparser.BoolVal:
 		return bool(node), nil
 	case *sqlparser.NullVal:
 		return nil, nil
 	case *sqlparser.UnaryExpr:
-		// 处理一元表达式，如 -1.5
+		// 处理一元表达式，如 -1.5、-salary
 		value, err := e.getValue(node.Expr)
 		if err != nil {
 			return nil, err
 		}
+		if value == nil {
+			return nil, nil
+		}
 
 		// 根据操作符处理值
 		switch node.Operator {
 		case sqlparser.MinusStr:
 			// 处理负数
-			switch v := value.(type) {
-			case int:
-				return -v, nil
-			case float64:
-				return -v, nil
-			default:
-				return nil, fmt.Errorf("不支持的操作符 %s 应用于类型 %T", node.Operator, value)
+			return negate(value)
+		case sqlparser.UPlusStr:
+			return e.toExactNumber(value)
+		case sqlparser.TildaStr:
+			// 按位取反，结果为64位无符号整数
+			number, err := toNumber(value)
+			if err != nil {
+				return nil, err
 			}
+			return normalizeUint(^toBits(number)), nil
 		default:
-			return nil, fmt.Errorf("不支持的一元操作符: %s", node.Operator)
+			return nil, newUnsupported(constructOperator, node.Operator)
 		}
+	case *sqlparser.BinaryExpr:
+		return e.evaluateBinaryExpr(node)
+	case *sqlparser.FuncExpr:
+		if node.Name.EqualString(xorFuncName) {
+			return e.getConditionValue(node)
+		}
+		value, err := e.evaluateFuncExpr(node)
+		if err != nil {
+			return nil, err
+		}
+		return e.exactNumber(value), nil
+	case *sqlparser.SubstrExpr:
+		return e.evaluateSubstrExpr(node)
+	case *sqlparser.CaseExpr:
+		return e.evaluateCaseExpr(node)
+	case *sqlparser.ParenExpr:
+		return e.getValue(node.Expr)
+	case *sqlparser.ComparisonExpr, *sqlparser.AndExpr, *sqlparser.OrExpr, *sqlparser.NotExpr, *sqlparser.RangeCond, *sqlparser.IsExpr:
+		// 条件作为函数参数等值使用，如 IF(age >= 18, 'adult', 'minor')
+		return e.getConditionValue(node)
+	default:
+		return nil, newUnsupportedExpr(expr)
+	}
+}
+
+// getConditionValue 将条件表达式的结果作为值，UNKNOWN 视为NULL
+func (e *SQLEvaluator) getConditionValue(expr sqlparser.Expr) (interface{}, error) {
+	result, err := e.evaluateExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	switch result {
+	case truthTrue:
+		return true, nil
+	case truthFalse:
+		return false, nil
 	default:
-		return nil, fmt.Errorf("不支持的表达式类型: %T", expr)
+		return nil, nil
 	}
 }
 
 // getFieldValue 获取字段值
+//
+// fieldName 为 getFieldName 解析得到的字段路径，嵌套字段以点分隔（如 Address.City），
+// 路径上任意一个nil指针或nil接口都视为NULL。
 func (e *SQLEvaluator) getFieldValue(fieldName string) (interface{}, error) {
 	// 获取字段的反射值
-	modelValue := reflect.ValueOf(e.model)
-	if modelValue.Kind() == reflect.Ptr {
-		modelValue = modelValue.Elem()
-	}
+	current := reflect.ValueOf(e.model)
+	names := strings.Split(fieldName, ".")
+	for i, name := range names {
+		// 解开指针和接口
+		for current.Kind() == reflect.Ptr || current.Kind() == reflect.Interface {
+			if current.IsNil() {
+				return nil, nil
+			}
+			current = current.Elem()
+		}
 
-	field := modelValue.FieldByName(fieldName)
-	if !field.IsValid() {
-		return nil, fmt.Errorf("field %s not found", fieldName)
+		switch current.Kind() {
+		case reflect.Struct:
+			structField, ok := current.Type().FieldByName(name)
+			if !ok {
+				return nil, newFieldNotFound(fieldName)
+			}
+			// 经过nil的匿名嵌入指针时视为NULL
+			field, err := current.FieldByIndexErr(structField.Index)
+			if err != nil {
+				return nil, nil
+			}
+			current = field
+		case reflect.Map:
+			if current.Type().Key().Kind() != reflect.String {
+				return nil, newUnsupported(constructMapKey, current.Type().Key().String())
+			}
+			current = current.MapIndex(reflect.ValueOf(name).Convert(current.Type().Key()))
+			// 键不存在视为NULL
+			if !current.IsValid() {
+				return nil, nil
+			}
+		default:
+			return nil, newNotContainer(names, i)
+		}
 	}
 
-	// 处理指针类型
-	if field.Kind() == reflect.Ptr {
-		if field.IsNil() {
+	return normalizeValue(current)
+}
+
+// normalizeValue 将反射值转换为评估使用的值，nil指针和nil接口视为NULL
+//
+// 指针和接口被解开，*big.Rat 和实现了 Decimal 接口的值转换为 *big.Rat，
+// 实现了 driver.Valuer 接口的值（如 sql.NullString）转换为 Value 方法返回的值，
+// 所有宽度的整数和浮点数转换为 int64、uint64 或 float64，底层类型为字符串或布尔的自定义类型转换为 string 或 bool。
+func normalizeValue(value reflect.Value) (interface{}, error) {
+	// 处理接口类型（如 map[string]interface{} 的值）和指针类型，解开前先检查是否实现了 Decimal 或 driver.Valuer
+	for {
+		if decimal, ok := normalizeDecimal(value); ok {
+			return decimal, nil
+		}
+		isIndirect := value.Kind() == reflect.Interface || value.Kind() == reflect.Ptr
+		if isIndirect && value.IsNil() {
 			return nil, nil
 		}
-		// 获取指针指向的值
-		elemValue := field.Elem()
-		switch elemValue.Kind() {
-		case reflect.Int:
-			return int(elemValue.Int()), nil
-		case reflect.Float64:
-			return elemValue.Float(), nil
-		case reflect.String:
-			return elemValue.String(), nil
-		case reflect.Bool:
-			return elemValue.Bool(), nil
-		default:
-			return elemValue.Interface(), nil
+		if valuer, ok := asInterface(value, valuerInterface); ok {
+			return normalizeDriverValue(valuer.(driver.Valuer))
 		}
+		if !isIndirect {
+			break
+		}
+		value = value.Elem()
 	}
 
-	return field.Interface(), nil
+	if number, ok := normalizeNumber(value); ok {
+		return number, nil
+	}
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return value.Bool(), nil
+	default:
+		return value.Interface(), nil
+	}
 }
 
-// compareValues 比较两个值
-func compareValues(a, b interface{}, compare func(float64, float64) bool) (bool, error) {
-	switch v1 := a.(type) {
-	case int:
-		v2, ok := b.(int)
-		if !ok {
-			return false, fmt.Errorf("类型不匹配: %T 和 %T", a, b)
+// compareValues 比较两个值，compare 接收比较结果（-1、0或1）并返回是否满足条件，operator 用于错误信息
+func compareValues(a, b interface{}, operator string, compare func(int) bool) (bool, error) {
+	if isNumber(a) {
+		if !isNumber(b) {
+			return false, newTypeMismatch(a, b, operator)
 		}
-		return compare(float64(v1), float64(v2)), nil
-	case float64:
-		v2, ok := b.(float64)
-		if !ok {
-			return false, fmt.Errorf("类型不匹配: %T 和 %T", a, b)
-		}
-		return compare(v1, v2), nil
+		return compare(compareNumbers(a, b)), nil
+	}
+
+	switch v1 := a.(type) {
 	case string:
 		v2, ok := b.(string)
 		if !ok {
-			return false, fmt.Errorf("类型不匹配: %T 和 %T", a, b)
+			return false, newTypeMismatch(a, b, operator)
 		}
-		return compare(float64(strings.Compare(v1, v2)), 0), nil
+		return compare(strings.Compare(v1, v2)), nil
+	case time.Time:
+		v2, ok := b.(time.Time)
+		if !ok {
+			return false, newTypeMismatch(a, b, operator)
+		}
+		return compare(v1.Compare(v2)), nil
 	case bool:
 		v2, ok := b.(bool)
 		if !ok {
-			return false, fmt.Errorf("类型不匹配: %T 和 %T", a, b)
+			return false, newTypeMismatch(a, b, operator)
 		}
 		if v1 == v2 {
 			return true, nil
 		}
 		return false, nil
 	default:
-		return false, fmt.Errorf("不支持的比较类型: %T", a)
+		return false, newTypeMismatch(a, b, operator)
 	}
 }
 
-// isTypeCompatible 检查两个值是否类型兼容
-func isTypeCompatible(a, b interface{}) bool {
-	if a == nil || b == nil {
-		return true
+// valuesEqual 判断两个已转换类型的值是否相等
+func valuesEqual(a, b interface{}) bool {
+	// 数值按值比较，int64(1)、uint64 和 float64(1) 可以相等
+	if isNumber(a) && isNumber(b) {
+		return compareNumbers(a, b) == 0
 	}
-
-	switch a.(type) {
-	case string:
-		_, ok := b.(string)
-		return ok
-	case int:
-		_, ok := b.(int)
-		return ok
-	case float64:
-		_, ok := b.(float64)
-		return ok
-	case bool:
-		_, ok := b.(bool)
-		return ok
-	default:
-		return false
+	// time.Time 包含时区和单调时钟信息，需要按时刻比较
+	if t1, ok := a.(time.Time); ok {
+		t2, ok := b.(time.Time)
+		return ok && t1.Equal(t2)
 	}
+	return reflect.DeepEqual(a, b)
 }
 
-// convertTypes 尝试转换类型使其兼容
-func convertTypes(a, b interface{}) (interface{}, interface{}, error) {
+// convertTypes 尝试转换类型使其兼容，operator 用于错误信息
+func (e *SQLEvaluator) convertTypes(a, b interface{}, operator string) (interface{}, interface{}, error) {
 	// 如果任一值为nil，直接返回
 	if a == nil || b == nil {
 		return a, b, nil
 	}
 
-	// 尝试将int转换为float64
-	switch v1 := a.(type) {
-	case int:
-		switch v2 := b.(type) {
-		case float64:
-			return float64(v1), v2, nil
-		case int:
-			return v1, v2, nil
-		case string:
-			// 尝试将字符串转换为数字
-			if f, err := strconv.ParseFloat(v2, 64); err == nil {
-				return float64(v1), f, nil
-			}
+	// 数值之间由 compareNumbers 精确比较，不需要转换
+	if isNumber(a) {
+		if isNumber(b) {
+			return a, b, nil
 		}
-	case float64:
-		switch v2 := b.(type) {
-		case int:
-			return v1, float64(v2), nil
-		case float64:
-			return v1, v2, nil
-		case string:
-			// 尝试将字符串转换为数字
-			if f, err := strconv.ParseFloat(v2, 64); err == nil {
-				return v1, f, nil
+		// 尝试将字符串转换为数字
+		if s, ok := b.(string); ok {
+			if number, ok := e.parseExactNumber(s); ok {
+				return a, number, nil
 			}
 		}
+	}
+
+	switch v1 := a.(type) {
 	case string:
 		switch v2 := b.(type) {
 		case string:
 			return v1, v2, nil
-		case int:
+		case int64, uint64, float64, *big.Rat:
 			// 尝试将字符串转换为数字
-			if f, err := strconv.ParseFloat(v1, 64); err == nil {
-				return f, float64(v2), nil
+			if number, ok := e.parseExactNumber(v1); ok {
+				return number, v2, nil
 			}
-		case float64:
-			// 尝试将字符串转换为数字
-			if f, err := strconv.ParseFloat(v1, 64); err == nil {
-				return f, v2, nil
+		case time.Time:
+			// 按配置的时区解析时间字面量
+			t, err := parseTime(v1, e.timeLocation(v2))
+			if err != nil {
+				return a, b, err
 			}
+			return t, v2, nil
+		}
+	case time.Time:
+		switch v2 := b.(type) {
+		case time.Time:
+			return v1, v2, nil
+		case string:
+			// 按配置的时区解析时间字面量
+			t, err := parseTime(v2, e.timeLocation(v1))
+			if err != nil {
+				return a, b, err
+			}
+			return v1, t, nil
 		}
 	case bool:
 		switch v2 := b.(type) {
@@ -683,72 +855,208 @@ func convertTypes(a, b interface{}) (interface{}, interface{}, error) {
 		return a, b, nil
 	}
 
-	return a, b, fmt.Errorf("无法转换类型: %T 和 %T", a, b)
+	return a, b, newTypeMismatch(a, b, operator)
 }
 
 // getFieldName 从SQL表达式中获取字段名
+//
+// 限定列名（如 address.city）逐级解析，返回以点分隔的Go字段路径（如 Address.City）；
+// 结构体的每一级都按 json 标签、字段名、下划线命名的顺序匹配，map 的每一级直接使用列名作为键。
+// 第一级不是结构体模型中的字段时视为表名，如 users.age 与 age 相同。
 func (e *SQLEvaluator) getFieldName(expr sqlparser.Expr) (string, error) {
 	switch v := expr.(type) {
 	case *sqlparser.ColName:
-		sqlName := v.Name.String()
-
-		// 检查结构体
-		modelValue := reflect.ValueOf(e.model)
-		if modelValue.Kind() == reflect.Ptr {
-			modelValue = modelValue.Elem()
-		}
-		modelType := modelValue.Type()
-
-		// 1. 尝试匹配 json 标签
-		for i := 0; i < modelType.NumField(); i++ {
-			field := modelType.Field(i)
-			tag := field.Tag.Get("json")
-			// 处理带选项的标签，如 `json:"name,omitempty"`
-			tagParts := strings.Split(tag, ",")
-			if len(tagParts) > 0 && tagParts[0] == sqlName {
-				return field.Name, nil
+		return e.resolveFieldPath(columnPath(v))
+	default:
+		return "", newUnsupportedExpr(expr)
+	}
+}
+
+// resolveFieldPath 将以点分隔的SQL列名解析为Go字段路径，规则见 getFieldName
+func (e *SQLEvaluator) resolveFieldPath(sqlName string) (string, error) {
+	segments := strings.Split(sqlName, ".")
+	resolved := make([]string, 0, len(segments))
+
+	// 同时跟踪值和类型：值为nil时仍按静态类型解析，以便区分拼写错误和NULL
+	currentValue := reflect.ValueOf(e.model)
+	currentType := reflect.TypeOf(e.model)
+	for i, segment := range segments {
+		for currentType != nil && (currentType.Kind() == reflect.Ptr || currentType.Kind() == reflect.Interface) {
+			if currentValue.IsValid() && !currentValue.IsNil() {
+				currentValue = currentValue.Elem()
+				currentType = currentValue.Type()
+				continue
+			}
+			currentValue = reflect.Value{}
+			if currentType.Kind() == reflect.Interface {
+				break
 			}
+			currentType = currentType.Elem()
 		}
 
-		// 2. 首先尝试直接匹配字段名（不区分大小写）
-		for i := 0; i < modelType.NumField(); i++ {
-			field := modelType.Field(i)
-			if strings.EqualFold(field.Name, sqlName) {
-				return field.Name, nil
+		if currentType == nil || currentType.Kind() == reflect.Interface {
+			// nil接口的动态类型未知，其下的字段必然为NULL，剩余部分原样保留
+			resolved = append(resolved, segments[i:]...)
+			break
+		}
+
+		switch currentType.Kind() {
+		case reflect.Struct:
+			field, ok := findStructField(currentType, segment)
+			if !ok {
+				// 第一级不是字段时视为表名等限定名，如 users.age 按 age 解析
+				if rest, qualified := unqualified(sqlName); i == 0 && qualified {
+					if path, err := e.resolveFieldPath(rest); err == nil {
+						return path, nil
+					}
+				}
+				return "", newFieldNotFound(sqlName)
+			}
+			resolved = append(resolved, field.Name)
+			currentType = field.Type
+			if currentValue.IsValid() {
+				// 经过nil的匿名嵌入指针时返回错误，此时只按类型继续解析
+				currentValue, _ = currentValue.FieldByIndexErr(field.Index)
+			}
+		case reflect.Map:
+			resolved = append(resolved, segment)
+			currentType = currentType.Elem()
+			if currentValue.IsValid() && currentValue.Type().Key().Kind() == reflect.String {
+				currentValue = currentValue.MapIndex(reflect.ValueOf(segment).Convert(currentValue.Type().Key()))
+			} else {
+				currentValue = reflect.Value{}
 			}
+		default:
+			return "", newNotContainer(segments, i)
 		}
+	}
+
+	return strings.Join(resolved, "."), nil
+}
+
+// findStructField 在结构体类型中查找SQL列名对应的字段，包括匿名嵌入结构体提升的字段，结果缓存在 columnCache 中
+func findStructField(structType reflect.Type, sqlName string) (reflect.StructField, bool) {
+	info := resolveColumn(structType, sqlName)
+	if info.err != nil {
+		return reflect.StructField{}, false
+	}
+	return info.field, true
+}
 
-		// 3. 尝试将下划线命名转换为驼峰命名
-		parts := strings.Split(sqlName, "_")
-		for i := range parts {
-			parts[i] = strings.Title(strings.ToLower(parts[i]))
+// lookupStructField 按 json 标签、字段名（不区分大小写）、下划线转驼峰的顺序查找字段
+func lookupStructField(structType reflect.Type, sqlName string) (reflect.StructField, bool) {
+	fields := make([]reflect.StructField, 0, structType.NumField())
+	for _, field := range reflect.VisibleFields(structType) {
+		// 未导出的字段无法读取，匿名嵌入字段的导出字段已被提升
+		if field.IsExported() {
+			fields = append(fields, field)
 		}
-		fieldName := strings.Join(parts, "")
+	}
 
-		field := modelValue.FieldByName(fieldName)
-		if field.IsValid() {
-			return fieldName, nil
+	names := make([]string, len(fields))
+	tags := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+		tags[i] = field.Tag.Get("json")
+	}
+	if i := matchColumn(names, tags, sqlName); i >= 0 {
+		return fields[i], true
+	}
+	return reflect.StructField{}, false
+}
+
+// matchColumn 按 json 标签、字段名（不区分大小写）、下划线转驼峰的顺序匹配列名，返回字段的下标，未匹配时返回-1
+//
+// names 为Go字段名，tags 为对应字段的 json 标签。
+func matchColumn(names, tags []string, sqlName string) int {
+	// 1. 尝试匹配 json 标签
+	for i, tag := range tags {
+		// 处理带选项的标签，如 `json:"name,omitempty"`
+		tagParts := strings.Split(tag, ",")
+		if len(tagParts) > 0 && tagParts[0] == sqlName {
+			return i
 		}
+	}
 
-		return "", fmt.Errorf("字段未找到: %s", sqlName)
-	default:
-		return "", fmt.Errorf("不支持的表达式类型: %T", expr)
+	// 2. 首先尝试直接匹配字段名（不区分大小写）
+	for i, name := range names {
+		if strings.EqualFold(name, sqlName) {
+			return i
+		}
+	}
+
+	// 3. 尝试将下划线命名转换为驼峰命名
+	parts := strings.Split(sqlName, "_")
+	for i := range parts {
+		parts[i] = strings.Title(strings.ToLower(parts[i]))
+	}
+	fieldName := strings.Join(parts, "")
+
+	for i, name := range names {
+		if name == fieldName {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// columnPath 返回列名的完整路径，限定名各部分以点连接
+func columnPath(col *sqlparser.ColName) string {
+	parts := make([]string, 0, 3)
+	if !col.Qualifier.Qualifier.IsEmpty() {
+		parts = append(parts, col.Qualifier.Qualifier.String())
 	}
+	if !col.Qualifier.Name.IsEmpty() {
+		parts = append(parts, col.Qualifier.Name.String())
+	}
+	parts = append(parts, col.Name.String())
+	return strings.Join(parts, ".")
+}
+
+// unqualified 返回去掉第一级限定名的列名，如 users.age 返回 age，列名没有限定名时返回false
+//
+// 列名的第一级不是模型中的字段时视为表名等限定名，按剩余部分解析，与不支持嵌套字段时忽略限定名的行为一致。
+func unqualified(column string) (string, bool) {
+	_, rest, ok := strings.Cut(column, ".")
+	return rest, ok
 }
 
 // getSQLValues 获取SQL值列表
 func (e *SQLEvaluator) getSQLValues(expr sqlparser.Expr) ([]interface{}, error) {
 	switch node := expr.(type) {
 	case sqlparser.ValTuple:
-		values := make([]interface{}, len(node))
-		for i, val := range node {
+		values := make([]interface{}, 0, len(node))
+		for _, val := range node {
+			// 绑定为切片的参数展开为多个值，如 id IN (?)
+			if sqlVal, ok := val.(*sqlparser.SQLVal); ok && sqlVal.Type == sqlparser.ValArg {
+				param, err := e.getParam(strings.TrimPrefix(string(sqlVal.Val), ":"))
+				if err != nil {
+					return nil, err
+				}
+				if list, ok := param.([]interface{}); ok {
+					values = append(values, list...)
+					continue
+				}
+			}
+
 			value, err := e.getValue(val)
 			if err != nil {
 				return nil, err
 			}
-			values[i] = value
+			values = append(values, value)
 		}
 		return values, nil
+	case sqlparser.ListArg:
+		// 处理列表参数，如 id IN ::ids
+		param, err := e.getParam(strings.TrimPrefix(string(node), "::"))
+		if err != nil {
+			return nil, err
+		}
+		if list, ok := param.([]interface{}); ok {
+			return list, nil
+		}
+		return []interface{}{param}, nil
 	case *sqlparser.UnaryExpr:
 		// 处理一元表达式，如 -1.5
 		value, err := e.getValue(node.Expr)
@@ -760,21 +1068,18 @@ func (e *SQLEvaluator) getSQLValues(expr sqlparser.Expr) ([]interface{}, error)
 		switch node.Operator {
 		case sqlparser.MinusStr:
 			// 处理负数
-			switch v := value.(type) {
-			case int:
-				return []interface{}{-v}, nil
-			case float64:
-				return []interface{}{-v}, nil
-			default:
-				return nil, fmt.Errorf("不支持的操作符 %s 应用于类型 %T", node.Operator, value)
+			negated, err := negate(value)
+			if err != nil {
+				return nil, err
 			}
+			return []interface{}{negated}, nil
 		default:
-			return nil, fmt.Errorf("不支持的一元操作符: %s", node.Operator)
+			return nil, newUnsupported(constructOperator, node.Operator)
 		}
 	case *sqlparser.ParenExpr:
 		// 处理括号表达式，如 (-1.5, -2.5)
 		return e.getSQLValues(node.Expr)
 	default:
-		return nil, fmt.Errorf("不支持的表达式类型: %T", expr)
+		return nil, newUnsupportedExpr(expr)
 	}
 }
@@ -0,0 +1,37 @@
+package sqlevaluator
+
+import "time"
+
+// timeLayouts 支持的不带时区的时间字面量格式
+var timeLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05.999999999",
+}
+
+// parseTime 解析时间字面量
+//
+// 带时区的RFC3339格式按字面量自身的时区解析，其余格式按loc解析。
+func parseTime(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, newConversionError(s, typeTime, "")
+}
+
+// timeLocation 返回与时间字段比较时解析字面量使用的时区
+func (e *SQLEvaluator) timeLocation(t time.Time) *time.Location {
+	if e.options.location != nil {
+		return e.options.location
+	}
+	return t.Location()
+}
@@ -0,0 +1,150 @@
+package sqlevaluator
+
+import (
+	"testing"
+	"time"
+)
+
+// Event 示例事件模型（包含时间字段）
+type Event struct {
+	ID        int        `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// timePtr 返回time.Time的指针
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestSQLEvaluatorTimeValues(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	event := &Event{
+		ID:        1,
+		CreatedAt: time.Date(2024, 3, 21, 10, 0, 0, 0, time.UTC),
+		ExpiresAt: timePtr(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)),
+	}
+
+	tests := []struct {
+		name        string
+		model       interface{}
+		whereClause string
+		opts        []Option
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "日期时间相等比较",
+			model:       event,
+			whereClause: "created_at = '2024-03-21 10:00:00'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "日期比较",
+			model:       event,
+			whereClause: "created_at > '2024-03-21' AND created_at < '2024-03-22'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "RFC3339比较",
+			model:       event,
+			whereClause: "created_at = '2024-03-21T18:00:00+08:00'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "时间指针字段比较",
+			model:       event,
+			whereClause: "expires_at >= '2024-12-31'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "时间范围比较",
+			model:       event,
+			whereClause: "created_at BETWEEN '2024-03-01' AND '2024-03-31 23:59:59'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "时间不在范围内",
+			model:       event,
+			whereClause: "created_at NOT BETWEEN '2024-01-01' AND '2024-02-01'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "时间列表比较",
+			model:       event,
+			whereClause: "expires_at IN ('2024-06-30', '2024-12-31')",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "nil时间指针视为NULL",
+			model:       &Event{ID: 2, CreatedAt: event.CreatedAt},
+			whereClause: "expires_at IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "nil时间指针比较",
+			model:       &Event{ID: 2, CreatedAt: event.CreatedAt},
+			whereClause: "expires_at > '2024-01-01'",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "指定时区解析字面量",
+			model:       event,
+			whereClause: "created_at = '2024-03-21 18:00:00'",
+			opts:        []Option{WithLocation(shanghai)},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "默认按字段时区解析字面量",
+			model:       &Event{CreatedAt: time.Date(2024, 3, 21, 18, 0, 0, 0, shanghai)},
+			whereClause: "created_at = '2024-03-21 18:00:00'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "非法时间字面量",
+			model:       event,
+			whereClause: "created_at > 'yesterday'",
+			want:        false,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(tt.model, tt.opts...)
+			got, err := evaluator.EvaluateWhere(tt.whereClause)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompiledWhereWithLocation(t *testing.T) {
+	compiled := MustCompile("created_at >= '2024-03-21 08:00:00'", WithLocation(time.FixedZone("CST", 8*3600)))
+
+	got, err := compiled.Evaluate(&Event{CreatedAt: time.Date(2024, 3, 21, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if !got {
+		t.Errorf("Evaluate() = %v, want %v", got, true)
+	}
+}
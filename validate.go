@@ -0,0 +1,690 @@
+package sqlevaluator
+
+import (
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// ValidationError 校验WHERE子句时发现的错误
+type ValidationError struct {
+	// Pos 出错片段在子句中的字节偏移，从0开始，无法定位时为-1
+	Pos int
+	// Fragment 出错的SQL片段
+	Fragment string
+	// Err 错误原因，字段未找到、类型不匹配等错误为 SQLError
+	Err error
+	// lang 错误信息的语言
+	lang Language
+}
+
+// Error 返回带位置的错误信息
+func (e *ValidationError) Error() string {
+	if _, ok := e.Err.(SQLError); ok {
+		return e.Err.Error()
+	}
+	return e.lang.locate(e.Err.Error(), Location{Pos: e.Pos, Fragment: e.Fragment})
+}
+
+// Unwrap 返回错误原因
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors 子句中的全部校验错误，按在子句中的位置排列
+type ValidationErrors []*ValidationError
+
+// Error 返回所有错误信息，以分号分隔
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap 返回全部错误，因此可以用 errors.As 获取其中的 SQLError
+func (errs ValidationErrors) Unwrap() []error {
+	result := make([]error, len(errs))
+	for i, err := range errs {
+		result[i] = err
+	}
+	return result
+}
+
+// Validate 在评估前按模型类型检查WHERE子句，返回发现的全部错误，子句有效时返回nil
+//
+// model 可以是模型的值或指针（如 (*User)(nil)）、模型的 reflect.Type、*Schema，
+// 或实现了 SchemaProvider 的模型。检查的内容包括：
+//   - 语法错误、未知函数和参数个数
+//   - 列按与评估时相同的规则解析，如拼写错误的 agee
+//   - 比较双方能否按评估时的规则转换为同一类型，如布尔字段与 'abc' 比较
+//   - 操作符与操作数类型是否相符，如 LIKE 和 REGEXP 只能用于字符串，算术运算只能用于数值
+//
+// map、接口类型的字段和参数的类型在评估前无法确定，不做类型检查；字符串字段可能在评估时转换为数值或时间，
+// 只有字符串字面量按实际值检查。
+func Validate(whereClause string, model interface{}, opts ...Option) ValidationErrors {
+	return validate(whereClause, model, newOptions(opts), nil)
+}
+
+// validate 校验WHERE子句，local 为评估器注册的自定义函数
+func validate(whereClause string, model interface{}, opts options, local map[string]*userFunction) ValidationErrors {
+	columns, err := newColumnResolver(model)
+	if err != nil {
+		return ValidationErrors{newValidationError(resolveError(err, nil, opts.language), opts.language)}
+	}
+
+	expr, err := parseWhere(whereClause)
+	if err != nil {
+		return ValidationErrors{newValidationError(resolveError(err, nil, opts.language), opts.language)}
+	}
+	if expr == nil {
+		return nil
+	}
+	return validateExpr(expr, locateNodes(whereClause, expr), columns, opts, local)
+}
+
+// validateExpr 校验已解析的子句，locator 用于定位错误，不修改语法树
+func validateExpr(expr sqlparser.Expr, locator *clauseLocator, columns columnResolver, opts options, local map[string]*userFunction) ValidationErrors {
+	v := &validator{
+		e:         &SQLEvaluator{options: opts},
+		functions: local,
+		columns:   columns,
+		locator:   locator,
+	}
+	v.condition(expr)
+
+	// 无法定位的错误排在最后
+	sort.SliceStable(v.errs, func(i, j int) bool {
+		pi, pj := v.errs[i].Pos, v.errs[j].Pos
+		if pi < 0 || pj < 0 {
+			return pj < 0 && pi >= 0
+		}
+		return pi < pj
+	})
+	return v.errs
+}
+
+// newValidationError 将错误转换为校验错误，结构化错误使用其自身的位置
+func newValidationError(err error, lang Language) *ValidationError {
+	result := &ValidationError{Pos: -1, Err: err, lang: lang}
+	if located, ok := err.(SQLError); ok {
+		result.Pos, result.Fragment = located.Position()
+	}
+	return result
+}
+
+// columnResolver 返回列的Go类型，类型在评估前无法确定时返回nil
+type columnResolver func(column string) (reflect.Type, error)
+
+// newColumnResolver 根据模型、模型类型或列描述创建列解析函数
+func newColumnResolver(model interface{}) (columnResolver, error) {
+	switch m := model.(type) {
+	case nil:
+		return nil, newInvalidArgument(reasonModel, "nil", nil, msgNilModel)
+	case *Schema:
+		return m.columnType, nil
+	case reflect.Type:
+		return func(column string) (reflect.Type, error) {
+			return staticColumnType(m, column, 0)
+		}, nil
+	case SchemaProvider:
+		return m.SQLSchema().columnType, nil
+	case FieldGetter:
+		// 没有列描述时只能通过 GetField 检查列是否存在，值为nil时类型未知
+		return func(column string) (reflect.Type, error) {
+			value, _, ok := getFieldQualified(m, column)
+			if !ok {
+				return nil, newFieldNotFound(column)
+			}
+			return reflect.TypeOf(value), nil
+		}, nil
+	default:
+		modelType := reflect.TypeOf(model)
+		return func(column string) (reflect.Type, error) {
+			return staticColumnType(modelType, column, 0)
+		}, nil
+	}
+}
+
+// staticColumnType 按与 getFieldName 相同的规则从第start级开始解析列的Go类型
+//
+// 路径上有接口时返回nil；map的每一级按值类型继续解析。
+func staticColumnType(current reflect.Type, column string, start int) (reflect.Type, error) {
+	segments := strings.Split(column, ".")
+	for i := start; i < len(segments); i++ {
+		segment := segments[i]
+		for current.Kind() == reflect.Ptr {
+			current = current.Elem()
+		}
+
+		switch current.Kind() {
+		case reflect.Struct:
+			field, ok := findStructField(current, segment)
+			if !ok {
+				// 第一级不是字段时按去掉限定名的列名解析，如 users.age
+				if rest, qualified := unqualified(column); i == 0 && qualified {
+					if fieldType, err := staticColumnType(current, rest, 0); err == nil {
+						return fieldType, nil
+					}
+				}
+				return nil, newFieldNotFound(column)
+			}
+			current = field.Type
+		case reflect.Map:
+			if current.Key().Kind() != reflect.String {
+				return nil, newUnsupported(constructMapKey, current.Key().String())
+			}
+			current = current.Elem()
+		case reflect.Interface:
+			return nil, nil
+		default:
+			return nil, newNotContainer(segments, i)
+		}
+	}
+	return current, nil
+}
+
+// valueKind 校验时推断的值类型
+type valueKind int
+
+const (
+	// valueUnknown 评估前无法确定的类型，如接口字段和参数，不做检查
+	valueUnknown valueKind = iota
+	valueNull
+	valueNumber
+	valueString
+	valueBool
+	valueTime
+	// valueOther 切片、结构体等只能与同类型比较的值
+	valueOther
+)
+
+// operand 校验时推断的操作数
+type operand struct {
+	kind valueKind
+	// value 字面量的值；非字面量为同类型的示例值，用于按 convertTypes 的规则检查能否比较
+	value interface{}
+	// literal 是否为字面量
+	literal bool
+}
+
+var (
+	unknownOperand = operand{kind: valueUnknown}
+	nullOperand    = operand{kind: valueNull}
+	numberOperand  = operand{kind: valueNumber, value: int64(0)}
+	stringOperand  = operand{kind: valueString, value: ""}
+	boolOperand    = operand{kind: valueBool, value: false}
+)
+
+// literalOperand 返回字面量的操作数
+func literalOperand(value interface{}) operand {
+	op := operand{value: value, literal: true}
+	switch value.(type) {
+	case nil:
+		op.kind = valueNull
+	case int64, uint64, float64, *big.Rat:
+		op.kind = valueNumber
+	case string:
+		op.kind = valueString
+	case bool:
+		op.kind = valueBool
+	case time.Time:
+		op.kind = valueTime
+	default:
+		op.kind = valueOther
+	}
+	return op
+}
+
+// typeOperand 返回Go类型的字段按 normalizeValue 转换后的操作数
+func typeOperand(t reflect.Type) operand {
+	for t != nil {
+		if t == ratType || t.Implements(decimalInterface) || reflect.PtrTo(t).Implements(decimalInterface) {
+			return operand{kind: valueNumber, value: new(big.Rat)}
+		}
+		if t.Implements(valuerInterface) || reflect.PtrTo(t).Implements(valuerInterface) {
+			return nullableOperand(t)
+		}
+		if t.Kind() != reflect.Ptr {
+			break
+		}
+		t = t.Elem()
+	}
+	if t == nil {
+		return unknownOperand
+	}
+	if t == timeType {
+		return operand{kind: valueTime, value: time.Time{}}
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return unknownOperand
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return numberOperand
+	case reflect.Float32, reflect.Float64:
+		return operand{kind: valueNumber, value: float64(0)}
+	case reflect.String:
+		return stringOperand
+	case reflect.Bool:
+		return boolOperand
+	default:
+		return operand{kind: valueOther, value: reflect.Zero(t).Interface()}
+	}
+}
+
+// nullableOperand 返回 driver.Valuer 类型的操作数
+//
+// sql.NullString、sql.NullTime 等只有一个值字段和 Valid 字段的结构体按值字段的类型检查，其他类型的值在评估前无法确定。
+func nullableOperand(t reflect.Type) operand {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t.NumField() != 2 {
+		return unknownOperand
+	}
+	for i := 0; i < 2; i++ {
+		valid := t.Field(i)
+		if valid.Name == "Valid" && valid.Type.Kind() == reflect.Bool {
+			return typeOperand(t.Field(1 - i).Type)
+		}
+	}
+	return unknownOperand
+}
+
+// checked 判断操作数的类型是否已知
+func (op operand) checked() bool {
+	return op.kind != valueUnknown && op.kind != valueNull
+}
+
+// dynamicString 判断操作数是否为非字面量的字符串，其值可能在评估时转换为数值、时间或布尔值
+func (op operand) dynamicString() bool {
+	return op.kind == valueString && !op.literal
+}
+
+// commonOperand 返回多个可能结果的共同类型，类型不一致时为未知类型
+func commonOperand(ops ...operand) operand {
+	result := nullOperand
+	for _, op := range ops {
+		switch {
+		case op.kind == valueNull:
+			continue
+		case result.kind == valueNull:
+			result = operand{kind: op.kind, value: op.value}
+		case result.kind != op.kind:
+			return unknownOperand
+		}
+	}
+	return result
+}
+
+// validator 按模型类型检查语法树
+type validator struct {
+	// e 用于计算字面量和检查类型转换，不关联模型
+	e         *SQLEvaluator
+	functions map[string]*userFunction
+	columns   columnResolver
+	locator   *clauseLocator
+	errs      ValidationErrors
+}
+
+// report 记录节点上的错误，结构化错误已关联节点时使用关联的节点
+func (v *validator) report(node sqlparser.SQLNode, err error) {
+	lang := v.e.options.language
+	result := newValidationError(resolveError(withNode(err, node), v.locator, lang), lang)
+	if _, ok := err.(SQLError); !ok {
+		result.Pos, result.Fragment = v.locator.position(node)
+	}
+	if result.Pos < 0 {
+		result.Fragment = sqlparser.String(node)
+	}
+	v.errs = append(v.errs, result)
+}
+
+// condition 检查作为条件的表达式，与 evaluateExpr 对应
+func (v *validator) condition(expr sqlparser.Expr) {
+	switch node := expr.(type) {
+	case *sqlparser.ComparisonExpr:
+		if node.Operator == sqlparser.InStr || node.Operator == sqlparser.NotInStr {
+			v.inExpr(node)
+			return
+		}
+		v.comparison(node)
+	case *sqlparser.AndExpr:
+		v.condition(node.Left)
+		v.condition(node.Right)
+	case *sqlparser.OrExpr:
+		v.condition(node.Left)
+		v.condition(node.Right)
+	case *sqlparser.NotExpr:
+		v.condition(node.Expr)
+	case *sqlparser.ParenExpr:
+		v.condition(node.Expr)
+	case *sqlparser.RangeCond:
+		v.rangeCond(node)
+	case *sqlparser.IsExpr:
+		v.isExpr(node)
+	case *sqlparser.UnaryExpr:
+		if node.Operator == sqlparser.BangStr {
+			v.condition(node.Expr)
+			return
+		}
+		v.truthValue(node)
+	case *sqlparser.FuncExpr:
+		if node.Name.EqualString(xorFuncName) {
+			v.xor(node)
+			return
+		}
+		v.truthValue(node)
+	case *sqlparser.ColName, sqlparser.BoolVal, *sqlparser.SQLVal, *sqlparser.NullVal, *sqlparser.BinaryExpr, *sqlparser.SubstrExpr, *sqlparser.CaseExpr:
+		v.truthValue(node)
+	default:
+		v.report(expr, newUnsupportedExpr(expr))
+	}
+}
+
+// xor 检查XOR运算的操作数
+func (v *validator) xor(expr *sqlparser.FuncExpr) {
+	if len(expr.Exprs) < 2 {
+		v.report(expr, newInvalidArgument(reasonOperands, "XOR", nil, msgXorOperands))
+	}
+	for _, selectExpr := range expr.Exprs {
+		aliased, ok := selectExpr.(*sqlparser.AliasedExpr)
+		if !ok {
+			v.report(expr, newUnsupported(constructArgument, sqlparser.String(selectExpr)))
+			continue
+		}
+		v.condition(aliased.Expr)
+	}
+}
+
+// truthValue 检查作为条件的值能否转换为布尔值
+func (v *validator) truthValue(expr sqlparser.Expr) {
+	op := v.value(expr)
+	if !op.checked() || op.kind == valueNumber || op.kind == valueBool || op.dynamicString() {
+		return
+	}
+	if _, err := toBool(op.value); err != nil {
+		v.report(expr, err)
+	}
+}
+
+// comparison 检查比较表达式
+func (v *validator) comparison(expr *sqlparser.ComparisonExpr) {
+	left := v.value(expr.Left)
+	right := v.value(expr.Right)
+
+	if isLikeOperator(expr.Operator) || isRegexpOperator(expr.Operator) {
+		v.stringOperand(expr.Left, left, expr.Operator)
+		v.stringOperand(expr.Right, right, expr.Operator)
+		if expr.Escape != nil {
+			if escape := v.value(expr.Escape); escape.literal {
+				if _, err := v.e.likeEscape(expr); err != nil {
+					v.report(expr.Escape, err)
+				}
+			}
+		}
+		if isRegexpOperator(expr.Operator) && isStringLiteral(expr.Right) {
+			if _, err := compileRegexp(string(expr.Right.(*sqlparser.SQLVal).Val), v.e.options); err != nil {
+				v.report(expr.Right, err)
+			}
+		}
+		return
+	}
+
+	switch expr.Operator {
+	case "=", "!=", "<>", ">", ">=", "<", "<=":
+		v.comparable(expr, expr.Operator, left, right)
+	default:
+		v.report(expr, newUnsupported(constructOperator, expr.Operator))
+	}
+}
+
+// stringOperand 检查LIKE和REGEXP的操作数是否为字符串
+//
+// 评估时数值等类型会先转换为字符串，但对它们使用模式匹配通常是写错了条件，因此校验时视为错误。
+func (v *validator) stringOperand(expr sqlparser.Expr, op operand, operator string) {
+	if !op.checked() || op.kind == valueString {
+		return
+	}
+	v.report(expr, newConversionError(op.value, typeString, operator))
+}
+
+// comparable 检查两个操作数能否按 convertTypes 的规则转换后比较
+func (v *validator) comparable(node sqlparser.SQLNode, operator string, left, right operand) {
+	if !left.checked() || !right.checked() {
+		return
+	}
+	// 字符串字段的值在评估时才能确定能否转换，只有与切片等类型比较时必然失败
+	if (left.dynamicString() && right.kind != valueOther) || (right.dynamicString() && left.kind != valueOther) {
+		return
+	}
+
+	a, b, err := v.e.convertTypes(left.value, right.value, operator)
+	if err == nil && operator != "=" && operator != "!=" && operator != "<>" && operator != sqlparser.InStr && operator != sqlparser.NotInStr {
+		_, err = compareValues(a, b, operator, func(int) bool { return true })
+	}
+	if err != nil {
+		// 时间字面量无法解析等错误也按类型不匹配报告
+		v.report(node, newTypeMismatch(left.value, right.value, operator))
+	}
+}
+
+// inExpr 检查IN表达式，列表中的每一项都需要能与左操作数比较
+func (v *validator) inExpr(expr *sqlparser.ComparisonExpr) {
+	left := v.value(expr.Left)
+	switch list := expr.Right.(type) {
+	case sqlparser.ValTuple:
+		for _, item := range list {
+			v.comparable(item, expr.Operator, left, v.value(item))
+		}
+	case sqlparser.ListArg:
+	default:
+		v.report(expr.Right, newUnsupportedExpr(expr.Right))
+	}
+}
+
+// rangeCond 检查BETWEEN表达式
+func (v *validator) rangeCond(expr *sqlparser.RangeCond) {
+	left := v.value(expr.Left)
+	v.comparable(expr.From, expr.Operator, left, v.value(expr.From))
+	v.comparable(expr.To, expr.Operator, left, v.value(expr.To))
+}
+
+// isExpr 检查IS表达式
+func (v *validator) isExpr(expr *sqlparser.IsExpr) {
+	switch expr.Operator {
+	case sqlparser.IsTrueStr, sqlparser.IsNotTrueStr, sqlparser.IsFalseStr, sqlparser.IsNotFalseStr:
+		v.truthValue(expr.Expr)
+	case sqlparser.IsNullStr, sqlparser.IsNotNullStr:
+		v.value(expr.Expr)
+	default:
+		v.report(expr, newUnsupported(constructOperator, expr.Operator))
+	}
+}
+
+// value 推断表达式的值类型并检查其中的子表达式，与 getValue 对应
+func (v *validator) value(expr sqlparser.Expr) operand {
+	switch node := expr.(type) {
+	case *sqlparser.ColName:
+		t, err := v.columns(columnPath(node))
+		if err != nil {
+			v.report(node, err)
+			return unknownOperand
+		}
+		return typeOperand(t)
+	case *sqlparser.SQLVal:
+		if node.Type == sqlparser.ValArg {
+			// 参数在评估时绑定
+			return unknownOperand
+		}
+		return v.literal(node)
+	case sqlparser.BoolVal, *sqlparser.NullVal:
+		return v.literal(node)
+	case *sqlparser.UnaryExpr:
+		if node.Operator == sqlparser.BangStr {
+			v.condition(node)
+			return boolOperand
+		}
+		op := v.value(node.Expr)
+		if op.literal {
+			return v.literal(node)
+		}
+		v.numeric(node.Expr, op)
+		if op.kind == valueNull {
+			return nullOperand
+		}
+		return numberOperand
+	case *sqlparser.BinaryExpr:
+		left := v.value(node.Left)
+		right := v.value(node.Right)
+		if left.literal && right.literal {
+			return v.literal(node)
+		}
+		v.numeric(node.Left, left)
+		v.numeric(node.Right, right)
+		if left.kind == valueNull || right.kind == valueNull {
+			return nullOperand
+		}
+		return numberOperand
+	case *sqlparser.FuncExpr:
+		return v.function(node)
+	case *sqlparser.SubstrExpr:
+		v.value(node.Name)
+		v.numeric(node.From, v.value(node.From))
+		if node.To != nil {
+			v.numeric(node.To, v.value(node.To))
+		}
+		return stringOperand
+	case *sqlparser.CaseExpr:
+		return v.caseExpr(node)
+	case *sqlparser.ParenExpr:
+		return v.value(node.Expr)
+	case *sqlparser.ComparisonExpr, *sqlparser.AndExpr, *sqlparser.OrExpr, *sqlparser.NotExpr, *sqlparser.RangeCond, *sqlparser.IsExpr:
+		v.condition(node)
+		return boolOperand
+	default:
+		v.report(expr, newUnsupportedExpr(expr))
+		return unknownOperand
+	}
+}
+
+// literal 计算只包含字面量的表达式
+func (v *validator) literal(expr sqlparser.Expr) operand {
+	value, err := v.e.getValue(expr)
+	if err != nil {
+		v.report(expr, err)
+		return unknownOperand
+	}
+	return literalOperand(value)
+}
+
+// numeric 检查算术运算的操作数能否转换为数值
+func (v *validator) numeric(expr sqlparser.Expr, op operand) {
+	if !op.checked() || op.dynamicString() {
+		return
+	}
+	if _, err := v.e.toExactNumber(op.value); err != nil {
+		v.report(expr, err)
+	}
+}
+
+// function 检查函数调用，返回函数结果的类型
+func (v *validator) function(expr *sqlparser.FuncExpr) operand {
+	if expr.Name.EqualString(xorFuncName) {
+		v.xor(expr)
+		return boolOperand
+	}
+
+	args := make([]operand, 0, len(expr.Exprs))
+	for _, selectExpr := range expr.Exprs {
+		aliased, ok := selectExpr.(*sqlparser.AliasedExpr)
+		if !ok {
+			v.report(expr, newUnsupported(constructArgument, sqlparser.String(selectExpr)))
+			return unknownOperand
+		}
+		args = append(args, v.value(aliased.Expr))
+	}
+	if expr.Distinct || !expr.Qualifier.IsEmpty() {
+		v.report(expr, newUnsupported(constructFunction, sqlparser.String(expr)))
+		return unknownOperand
+	}
+
+	name := expr.Name.Lowered()
+	if fn, ok := builtinFunctions[name]; ok {
+		if err := checkArity(name, fn.minArgs, fn.maxArgs, len(args)); err != nil {
+			v.report(expr, err)
+			return unknownOperand
+		}
+		return v.builtinResult(expr, name, args)
+	}
+
+	f, ok := v.functions[name]
+	if !ok {
+		f, ok = defaultRegistry.lookup(name)
+	}
+	if !ok {
+		v.report(expr, newUnsupported(constructFunction, sqlparser.String(expr.Name)))
+		return unknownOperand
+	}
+	if err := checkArity(name, f.minArgs, f.maxArgs, len(args)); err != nil {
+		v.report(expr, err)
+		return unknownOperand
+	}
+	if err := checkLiteralArgs(f, expr, v.e.options); err != nil {
+		v.report(expr, err)
+	}
+	return typeOperand(f.fn.Type().Out(0))
+}
+
+// builtinResult 检查内置函数的参数，返回函数结果的类型
+func (v *validator) builtinResult(expr *sqlparser.FuncExpr, name string, args []operand) operand {
+	switch name {
+	case "length", "octet_length", "char_length", "character_length":
+		return numberOperand
+	case "abs", "ceil", "ceiling", "floor", "round", "mod", "pow", "power":
+		for i, arg := range args {
+			v.numeric(expr.Exprs[i].(*sqlparser.AliasedExpr).Expr, arg)
+		}
+		return numberOperand
+	case "coalesce", "ifnull":
+		return commonOperand(args...)
+	case "nullif":
+		return commonOperand(args[0])
+	case "if":
+		return commonOperand(args[1:]...)
+	default:
+		// 字符串函数
+		return stringOperand
+	}
+}
+
+// caseExpr 检查CASE表达式，返回各分支结果的共同类型
+func (v *validator) caseExpr(expr *sqlparser.CaseExpr) operand {
+	var subject operand
+	if expr.Expr != nil {
+		subject = v.value(expr.Expr)
+	}
+
+	results := make([]operand, 0, len(expr.Whens)+1)
+	for _, when := range expr.Whens {
+		if expr.Expr != nil {
+			v.comparable(when.Cond, "=", subject, v.value(when.Cond))
+		} else {
+			v.condition(when.Cond)
+		}
+		results = append(results, v.value(when.Val))
+	}
+	if expr.Else != nil {
+		results = append(results, v.value(expr.Else))
+	} else {
+		results = append(results, nullOperand)
+	}
+	return commonOperand(results...)
+}
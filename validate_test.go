@@ -0,0 +1,241 @@
+package sqlevaluator
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		whereClause string
+		model       interface{}
+		// want 错误的位置和片段，格式为 "位置:片段"
+		want []string
+	}{
+		{
+			name:        "有效的子句",
+			whereClause: "age > 20 AND name LIKE 'a%' AND is_active = true AND salary BETWEEN 1000 AND 2000.5",
+			model:       &UserWithNonPtr{},
+			want:        nil,
+		},
+		{
+			name:        "字段名拼写错误",
+			whereClause: "agee > 20",
+			model:       &UserWithNonPtr{},
+			want:        []string{"0:agee"},
+		},
+		{
+			name:        "布尔字段与无法转换的字符串比较",
+			whereClause: "is_active > 'abc'",
+			model:       &UserWithNonPtr{},
+			want:        []string{"0:is_active > 'abc'"},
+		},
+		{
+			name:        "可以转换的字符串字面量",
+			whereClause: "is_active = 'true' AND age > '18' AND name = 123",
+			model:       UserWithNonPtr{},
+			want:        nil,
+		},
+		{
+			name:        "LIKE只能用于字符串",
+			whereClause: "name LIKE 'a%' AND age LIKE '1%'",
+			model:       &UserWithNonPtr{},
+			want:        []string{"19:age"},
+		},
+		{
+			name:        "无效的正则表达式",
+			whereClause: "name REGEXP '[a-'",
+			model:       &UserWithNonPtr{},
+			want:        []string{"12:'[a-'"},
+		},
+		{
+			name:        "算术运算只能用于数值",
+			whereClause: "age + 'x' > 1 AND salary * 2 > 100",
+			model:       &UserWithNonPtr{},
+			want:        []string{"6:'x'"},
+		},
+		{
+			name:        "IN列表和BETWEEN的每一项",
+			whereClause: "age IN (1, 'a', 3) OR age BETWEEN 'b' AND 10",
+			model:       &UserWithNonPtr{},
+			want:        []string{"11:'a'", "34:'b'"},
+		},
+		{
+			name:        "报告全部错误",
+			whereClause: "(agee > 20 OR nmae = 'x') AND is_active",
+			model:       &UserWithNonPtr{},
+			want:        []string{"1:agee", "14:nmae"},
+		},
+		{
+			name:        "嵌套字段和匿名嵌入字段",
+			whereClause: "address.city = 'Beijing' AND billing_address.geo.zip = '1' AND created_by = 'admin' AND version > 1",
+			model:       (*Customer)(nil),
+			want:        nil,
+		},
+		{
+			name:        "嵌套字段不存在",
+			whereClause: "address.city = 'Beijing' AND address.street = 'x'",
+			model:       (*Customer)(nil),
+			want:        []string{"29:address.street"},
+		},
+		{
+			name:        "map中的字段类型未知",
+			whereClause: "metadata.source = 'app' AND metadata.level > 1",
+			model:       (*Customer)(nil),
+			want:        nil,
+		},
+		{
+			name:        "时间字段",
+			whereClause: "created_at > '2024-03-21' AND updated_at < 'yesterday'",
+			model:       &Quota{},
+			want:        []string{"30:updated_at < 'yesterday'"},
+		},
+		{
+			name:        "时间字段不能作为条件",
+			whereClause: "created_at",
+			model:       &Quota{},
+			want:        []string{"0:created_at"},
+		},
+		{
+			name:        "sql.Null类型按值字段检查",
+			whereClause: "name LIKE 'a%' AND verified = true AND created_at > 'never'",
+			model:       &Account{},
+			want:        []string{"39:created_at > 'never'"},
+		},
+		{
+			name:        "函数",
+			whereClause: "LOWER(name) = 'a' AND UPPER(name, 1) = 'A' AND unknown_func(name) = 1",
+			model:       &Member{},
+			want:        []string{"22:UPPER(name, 1)", "47:unknown_func(name)"},
+		},
+		{
+			name:        "函数结果的类型",
+			whereClause: "LENGTH(name) > 'x' AND COALESCE(salary, balance) > 100",
+			model:       &Member{},
+			want:        []string{"0:LENGTH(name) > 'x'"},
+		},
+		{
+			name:        "CASE表达式",
+			whereClause: "CASE WHEN points > 10 THEN 'vip' ELSE 'normal' END = 'vip' AND CASE nickname WHEN 1 THEN 2 END = 2",
+			model:       &Member{},
+			want:        nil,
+		},
+		{
+			name:        "XOR和NOT",
+			whereClause: "NOT (agee IS NULL) XOR is_active",
+			model:       &UserWithNonPtr{},
+			want:        []string{"5:agee"},
+		},
+		{
+			name:        "参数在评估时检查",
+			whereClause: "age > ? AND name IN :names",
+			model:       &UserWithNonPtr{},
+			want:        nil,
+		},
+		{
+			name:        "语法错误",
+			whereClause: "age > > 1",
+			model:       &UserWithNonPtr{},
+			want:        []string{"6:>"},
+		},
+		{
+			name:        "子句不完整",
+			whereClause: "age >",
+			model:       &UserWithNonPtr{},
+			want:        []string{"5:"},
+		},
+		{
+			name:        "空子句",
+			whereClause: "",
+			model:       &UserWithNonPtr{},
+			want:        []string{"0:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := Validate(tt.whereClause, tt.model)
+
+			var got []string
+			for _, err := range errs {
+				got = append(got, fmt.Sprintf("%d:%s", err.Pos, err.Fragment))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", errs, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateModelForms(t *testing.T) {
+	whereClause := "address.city = 'Beijing' AND agee > 1"
+
+	tests := []struct {
+		name    string
+		model   interface{}
+		wantErr bool
+	}{
+		{
+			name:    "模型的值",
+			model:   Customer{},
+			wantErr: false,
+		},
+		{
+			name:    "模型类型",
+			model:   reflect.TypeOf(Customer{}),
+			wantErr: false,
+		},
+		{
+			name:    "列描述",
+			model:   SchemaOf((*Customer)(nil)),
+			wantErr: false,
+		},
+		{
+			name:    "FieldGetter",
+			model:   &Profile{},
+			wantErr: false,
+		},
+		{
+			name:    "模型为nil",
+			model:   nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := Validate(whereClause, tt.model)
+
+			if tt.wantErr {
+				if len(errs) != 1 || errs[0].Pos != -1 {
+					t.Errorf("Validate() = %v, want 1 error without position", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].Fragment != "agee" {
+				t.Errorf("Validate() = %v, want error at agee", errs)
+			}
+		})
+	}
+}
+
+func TestSchemaLookup(t *testing.T) {
+	schema := SchemaOf((*Customer)(nil))
+
+	if i := schema.Lookup("name"); i < 0 || schema.Fields()[i].Name != "Name" {
+		t.Errorf("Lookup(name) = %d", i)
+	}
+	if _, cached := schema.columns.Load("name"); !cached {
+		t.Error("Lookup(name) 未缓存")
+	}
+
+	// 不存在的列名不缓存
+	if i := schema.Lookup("agee"); i != -1 {
+		t.Errorf("Lookup(agee) = %d, want -1", i)
+	}
+	if _, cached := schema.columns.Load("agee"); cached {
+		t.Error("Lookup(agee) 缓存了不存在的列名")
+	}
+}
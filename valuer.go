@@ -0,0 +1,51 @@
+package sqlevaluator
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+)
+
+var valuerInterface = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// asInterface 返回实现了 iface 接口的值，指针接收者实现的接口使用值的地址（不可寻址时使用副本的地址）
+func asInterface(value reflect.Value, iface reflect.Type) (interface{}, bool) {
+	if !value.IsValid() || value.Kind() == reflect.Interface || !value.CanInterface() {
+		return nil, false
+	}
+	if value.Type().Implements(iface) {
+		return value.Interface(), true
+	}
+	if reflect.PtrTo(value.Type()).Implements(iface) {
+		if !value.CanAddr() {
+			copied := reflect.New(value.Type()).Elem()
+			copied.Set(value)
+			value = copied
+		}
+		return value.Addr().Interface(), true
+	}
+	return nil, false
+}
+
+// normalizeDriverValue 将 driver.Valuer 的 Value 方法返回的值转换为评估使用的值
+//
+// sql.NullString、sql.NullInt64、sql.NullTime 等类型的 Valid 为false时 Value 返回nil，视为NULL；
+// []byte 转换为字符串。
+func normalizeDriverValue(valuer driver.Valuer) (interface{}, error) {
+	value, err := valuer.Value()
+	if err != nil {
+		return nil, newInvalidArgument(reasonValuer, fmt.Sprintf("%T", valuer), err, msgValuerFailed, fmt.Sprintf("%T", valuer), err)
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return string(v), nil
+	}
+	// 避免 Value 返回自身类型时无限递归
+	if reflect.TypeOf(value) == reflect.TypeOf(valuer) {
+		return nil, newInvalidArgument(reasonValuer, fmt.Sprintf("%T", valuer), nil, msgValuerSelf, fmt.Sprintf("%T", valuer))
+	}
+	return normalizeValue(reflect.ValueOf(value))
+}
@@ -0,0 +1,186 @@
+package sqlevaluator
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Status 实现了 driver.Valuer 的自定义类型，存储为大写字符串
+type Status string
+
+// Value 实现 driver.Valuer 接口
+func (s Status) Value() (driver.Value, error) {
+	return strings.ToUpper(string(s)), nil
+}
+
+// Tags 指针接收者实现 driver.Valuer 的类型
+type Tags struct {
+	items []string
+}
+
+// Value 实现 driver.Valuer 接口
+func (t *Tags) Value() (driver.Value, error) {
+	return []byte(strings.Join(t.items, ",")), nil
+}
+
+// Broken Value 方法总是返回错误的类型
+type Broken struct{}
+
+// Value 实现 driver.Valuer 接口
+func (Broken) Value() (driver.Value, error) {
+	return nil, errors.New("连接已关闭")
+}
+
+// Account 模拟sqlc生成的数据库模型
+type Account struct {
+	ID        int64           `json:"id"`
+	Name      sql.NullString  `json:"name"`
+	Nickname  sql.NullString  `json:"nickname"`
+	Age       sql.NullInt64   `json:"age"`
+	Score     sql.NullInt32   `json:"score"`
+	Balance   sql.NullFloat64 `json:"balance"`
+	Verified  sql.NullBool    `json:"verified"`
+	CreatedAt sql.NullTime    `json:"created_at"`
+	DeletedAt sql.NullTime    `json:"deleted_at"`
+	Referrer  *sql.NullInt64  `json:"referrer"`
+	Status    Status          `json:"status"`
+	Tags      Tags            `json:"tags"`
+	Broken    Broken          `json:"broken"`
+}
+
+func TestSQLEvaluatorDriverValuer(t *testing.T) {
+	account := Account{
+		ID:        1,
+		Name:      sql.NullString{String: "alice", Valid: true},
+		Nickname:  sql.NullString{String: "", Valid: false},
+		Age:       sql.NullInt64{Int64: 30, Valid: true},
+		Score:     sql.NullInt32{Int32: 0, Valid: false},
+		Balance:   sql.NullFloat64{Float64: 99.5, Valid: true},
+		Verified:  sql.NullBool{Bool: true, Valid: true},
+		CreatedAt: sql.NullTime{Time: time.Date(2024, 3, 21, 10, 0, 0, 0, time.UTC), Valid: true},
+		Status:    "active",
+		Tags:      Tags{items: []string{"vip", "beta"}},
+	}
+
+	tests := []struct {
+		name        string
+		whereClause string
+		args        []interface{}
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name:        "NullString有效",
+			whereClause: "name = 'alice' AND name LIKE 'al%'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "NullString无效视为NULL",
+			whereClause: "nickname IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "无效的NullString与空字符串比较",
+			whereClause: "nickname = ''",
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "NullInt64有效",
+			whereClause: "age BETWEEN 18 AND 65 AND age + 1 = 31",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "NullInt32无效视为NULL",
+			whereClause: "score IS NULL AND COALESCE(score, -1) = -1",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "NullFloat64和NullBool",
+			whereClause: "balance > 99 AND verified = true",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "NullTime有效",
+			whereClause: "created_at > '2024-01-01' AND created_at < '2024-12-31'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "NullTime无效视为NULL",
+			whereClause: "deleted_at IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "nil的Null类型指针视为NULL",
+			whereClause: "referrer IS NULL",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "自定义Valuer",
+			whereClause: "status = 'ACTIVE'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "指针接收者的Valuer返回[]byte",
+			whereClause: "tags = 'vip,beta'",
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "Null类型作为参数",
+			whereClause: "name = ? AND age = ? AND nickname IS NULL",
+			args:        []interface{}{sql.NullString{String: "alice", Valid: true}, sql.NullInt64{Int64: 30, Valid: true}},
+			want:        true,
+			wantErr:     false,
+		},
+		{
+			name:        "无效的Null类型参数视为NULL",
+			whereClause: "name = ?",
+			args:        []interface{}{sql.NullString{}},
+			want:        false,
+			wantErr:     false,
+		},
+		{
+			name:        "Value方法返回错误",
+			whereClause: "broken IS NULL",
+			want:        false,
+			wantErr:     true,
+		},
+		{
+			name:        "参数的Value方法返回错误",
+			whereClause: "id = ?",
+			args:        []interface{}{Broken{}},
+			want:        false,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewSQLEvaluator(account)
+			got, err := evaluator.EvaluateWhere(tt.whereClause, tt.args...)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvaluateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("EvaluateWhere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package sqlevaluator
+
+import (
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// xorFuncName XOR运算改写后使用的函数名
+//
+// sqlparser 不支持逻辑运算符 XOR，解析前将 `a XOR b XOR c` 改写为 `xor((a), (b), (c))`。
+const xorFuncName = "xor"
+
+// xorTokenType XOR运算符的词法单元类型，sqlparser 将 xor 识别为未使用的关键字
+const xorTokenType = -1
+
+// rewriteXor 将WHERE子句中的XOR运算改写为xor函数调用
+//
+// XOR的优先级低于AND、高于OR（与MySQL一致），因此按括号层级以OR、逗号和CASE关键字
+// 划分片段，片段内以XOR分隔的各部分即为XOR的操作数。不包含XOR时原样返回；
+// 包含XOR时会修改 tokens 中XOR的类型，改写后的子句需要重新做词法分析。
+func rewriteXor(clause string, tokens []clauseToken) string {
+	hasXor := false
+	for i, token := range tokens {
+		// 反引号括起来的 `xor` 是普通标识符
+		if token.typ == sqlparser.UNUSED && strings.EqualFold(token.text(clause), "xor") {
+			tokens[i].typ = xorTokenType
+			hasXor = true
+		}
+	}
+
+	if !hasXor {
+		return clause
+	}
+
+	rewritten, _ := rewriteXorTokens(clause, tokens)
+	return rewritten
+}
+
+// rewriteXorTokens 改写同一括号层级内的词法单元，遇到不匹配的右括号时返回，
+// 返回值为改写后的文本和已消费的词法单元数量
+func rewriteXorTokens(clause string, tokens []clauseToken) (string, int) {
+	var result strings.Builder
+	var segment []string
+	var operand strings.Builder
+
+	// flushOperand 结束当前XOR操作数
+	flushOperand := func() {
+		segment = append(segment, strings.TrimSpace(operand.String()))
+		operand.Reset()
+	}
+	// flushSegment 结束当前片段，包含多个操作数时输出xor函数调用
+	flushSegment := func() {
+		flushOperand()
+		if len(segment) == 1 {
+			result.WriteString(segment[0])
+		} else {
+			result.WriteString(xorFuncName + "((" + strings.Join(segment, "), (") + "))")
+		}
+		segment = segment[:0]
+	}
+
+	i := 0
+	for i < len(tokens) {
+		token := tokens[i]
+		text := token.text(clause)
+
+		switch token.typ {
+		case '(':
+			inner, consumed := rewriteXorTokens(clause, tokens[i+1:])
+			operand.WriteString(" (" + inner + ")")
+			i += consumed + 2
+			continue
+		case ')':
+			flushSegment()
+			return result.String(), i
+		case xorTokenType:
+			flushOperand()
+		case sqlparser.COMMENT:
+			// 解析时忽略注释，-- 和 # 注释延伸到行尾，写入操作数会吞掉改写后补上的右括号
+		case sqlparser.OR, ',', sqlparser.CASE, sqlparser.WHEN, sqlparser.THEN, sqlparser.ELSE, sqlparser.END:
+			flushSegment()
+			result.WriteString(" " + text + " ")
+		default:
+			operand.WriteString(" " + text)
+		}
+		i++
+	}
+
+	flushSegment()
+	return result.String(), i
+}
@@ -0,0 +1,70 @@
+package sqlevaluator
+
+import "testing"
+
+func TestRewriteXor(t *testing.T) {
+	tests := []struct {
+		name   string
+		clause string
+		want   string
+	}{
+		{
+			name:   "不包含XOR",
+			clause: "name = 'xor' AND `xor` = 1",
+			want:   "name = 'xor' AND `xor` = 1",
+		},
+		{
+			name:   "简单XOR",
+			clause: "a = 1 XOR b = 2",
+			want:   "xor((a = 1), (b = 2))",
+		},
+		{
+			name:   "多个XOR",
+			clause: "a xor b XOR c",
+			want:   "xor((a), (b), (c))",
+		},
+		{
+			name:   "AND优先于XOR",
+			clause: "a AND b XOR c",
+			want:   "xor((a AND b), (c))",
+		},
+		{
+			name:   "XOR优先于OR",
+			clause: "a OR b XOR c",
+			want:   "a OR xor((b), (c))",
+		},
+		{
+			name:   "括号内的XOR",
+			clause: "(a XOR b) AND c",
+			want:   "(xor((a), (b))) AND c",
+		},
+		{
+			name:   "BETWEEN中的AND",
+			clause: "age BETWEEN 1 AND 10 XOR NOT is_active",
+			want:   "xor((age BETWEEN 1 AND 10), (NOT is_active))",
+		},
+		{
+			name:   "行尾的--注释",
+			clause: "a XOR b -- c",
+			want:   "xor((a), (b))",
+		},
+		{
+			name:   "行尾的#注释",
+			clause: "a XOR b # c",
+			want:   "xor((a), (b))",
+		},
+		{
+			name:   "操作数之间的注释",
+			clause: "a /* x */ XOR -- y\nb",
+			want:   "xor((a), (b))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rewriteOnce(tt.clause, rewriteXor); got != tt.want {
+				t.Errorf("rewriteXor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}